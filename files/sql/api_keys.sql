@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS api_keys (
+    id           BIGINT AUTO_INCREMENT PRIMARY KEY,
+    key_hash     CHAR(64)     NOT NULL,
+    prefix       VARCHAR(16)  NOT NULL,
+    owner        VARCHAR(128) NOT NULL,
+    scopes       TEXT         NOT NULL,
+    created_at   DATETIME     NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    expires_at   DATETIME     NULL,
+    last_used_at DATETIME     NULL,
+    UNIQUE KEY uk_api_keys_key_hash (key_hash),
+    KEY idx_api_keys_owner (owner)
+);
@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS idempotency_keys (
+    idempotency_key CHAR(64)     NOT NULL PRIMARY KEY,
+    fingerprint     CHAR(64)     NOT NULL,
+    completed       TINYINT(1)   NOT NULL DEFAULT 0,
+    status_code     INT          NOT NULL DEFAULT 0,
+    content_type    VARCHAR(128) NOT NULL DEFAULT '',
+    body            MEDIUMBLOB   NULL,
+    created_at      DATETIME     NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    expires_at      DATETIME     NOT NULL,
+    KEY idx_idempotency_keys_expires_at (expires_at)
+);
@@ -0,0 +1,6 @@
+CREATE TABLE IF NOT EXISTS skeletons (
+    skeleton_id   INT AUTO_INCREMENT PRIMARY KEY,
+    skeleton_name VARCHAR(128) NOT NULL,
+    created_at    DATETIME     NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    KEY idx_skeletons_created_at (created_at, skeleton_id)
+);
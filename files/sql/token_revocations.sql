@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS token_revocations (
+    id            BIGINT AUTO_INCREMENT PRIMARY KEY,
+    jti           VARCHAR(128) NOT NULL DEFAULT '',
+    subject       VARCHAR(128) NOT NULL DEFAULT '',
+    issued_before DATETIME     NOT NULL,
+    expires_at    DATETIME     NOT NULL,
+    created_at    DATETIME     NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    KEY idx_token_revocations_expires_at (expires_at)
+);
@@ -3,10 +3,19 @@ package boot
 import (
 	"go-skeleton-auth/docs"
 	"go-skeleton-auth/internal/data/auth"
+	"go-skeleton-auth/pkg/authz"
+	"go-skeleton-auth/pkg/compression"
+	"go-skeleton-auth/pkg/corspolicy"
+	"go-skeleton-auth/pkg/grace"
+	"go-skeleton-auth/pkg/health"
 	"go-skeleton-auth/pkg/httpclient"
+	"go-skeleton-auth/pkg/ratelimit"
+	"go-skeleton-auth/pkg/tlsutil"
 	"go-skeleton-auth/pkg/tracing"
 	"log"
 	"net/http"
+	"net/netip"
+	"strings"
 
 	"go-skeleton-auth/internal/config"
 	jaegerLog "go-skeleton-auth/pkg/log"
@@ -15,9 +24,16 @@ import (
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 
+	apikeyData "go-skeleton-auth/internal/data/apikey"
+	idempotencyData "go-skeleton-auth/internal/data/idempotency"
+	revocationData "go-skeleton-auth/internal/data/revocation"
 	skeletonData "go-skeleton-auth/internal/data/skeleton"
 	skeletonServer "go-skeleton-auth/internal/delivery/http"
+	apikeyHandler "go-skeleton-auth/internal/delivery/http/apikey"
+	revocationHandler "go-skeleton-auth/internal/delivery/http/revocation"
 	skeletonHandler "go-skeleton-auth/internal/delivery/http/skeleton"
+	apikeyService "go-skeleton-auth/internal/service/apikey"
+	revocationService "go-skeleton-auth/internal/service/revocation"
 	skeletonService "go-skeleton-auth/internal/service/skeleton"
 )
 
@@ -37,6 +53,9 @@ func HTTP() error {
 	//
 	docs.SwaggerInfo.Host = cfg.Swagger.Host
 	docs.SwaggerInfo.Schemes = cfg.Swagger.Schemes
+	if cfg.Server.BasePath != "" {
+		docs.SwaggerInfo.BasePath = cfg.Server.BasePath
+	}
 
 	// Set logger used for jaeger
 	logger, _ := zap.NewDevelopment(
@@ -50,16 +69,167 @@ func HTTP() error {
 	tracer, closer := tracing.Init("skeleton", zlogger)
 	defer closer.Close()
 
-	httpc := httpclient.NewClient(tracer)
-	ad := auth.New(httpc, cfg.API.Auth)
+	// Client certificate for the auth API when it requires mutual TLS
+	var httpcOpts []httpclient.Option
+	if t := cfg.API.AuthTLS; t.CertFile != "" || t.CAFile != "" {
+		tlsCfg, err := tlsutil.NewClientConfig(tlsutil.ClientOptions{
+			CertFile:   t.CertFile,
+			KeyFile:    t.KeyFile,
+			CAFile:     t.CAFile,
+			ServerName: t.ServerName,
+			MinVersion: t.MinVersion,
+		})
+		if err != nil {
+			log.Fatalf("[TLS] Failed to initialize auth API client TLS: %v", err)
+		}
+		httpcOpts = append(httpcOpts, httpclient.WithTLSConfig(tlsCfg))
+	}
+
+	httpc := httpclient.NewClient(tracer, httpcOpts...)
+	// Rights checks are cached and coalesced in front of the auth API
+	authAPI := auth.New(httpc, cfg.API.Auth)
+	ad := auth.NewCached(authAPI, auth.WithBatch(cfg.API.AuthBatch))
+
+	// Load authorization policies, every authenticated request is allowed without a policy file
+	policies := authz.AllowAll()
+	if cfg.Authz.PolicyFile != "" {
+		if policies, err = authz.LoadPolicies(cfg.Authz.PolicyFile); err != nil {
+			log.Fatalf("[AUTHZ] Failed to load authorization policies: %v", err)
+		}
+	}
+	az := authz.NewEngine(policies, zlogger)
 
 	// Diganti dengan domain yang anda buat
 	sd := skeletonData.New(db, tracer, zlogger)
-	ss := skeletonService.New(sd, ad, tracer, zlogger)
+	ss := skeletonService.New(sd, ad, az, tracer, zlogger)
 	sh := skeletonHandler.New(ss, tracer, zlogger)
 
+	// API keys as an alternative to JWT
+	kd := apikeyData.New(db, tracer, zlogger)
+	ks := apikeyService.New(kd, cfg.APIKey.RotationOverlap, tracer, zlogger)
+	kh := apikeyHandler.New(ks, tracer, zlogger)
+
+	// Token revocation
+	rd := revocationData.New(db, tracer, zlogger)
+	rs := revocationService.New(rd, cfg.Revocation.SyncInterval, cfg.Revocation.MaxTokenLifetime, tracer, zlogger)
+	rh := revocationHandler.New(rs, tracer, zlogger)
+
 	s := skeletonServer.Server{
-		Skeleton: sh,
+		Skeleton:          sh,
+		APIKey:            ks,
+		APIKeyHandler:     kh,
+		Revocation:        rs,
+		RevocationHandler: rh,
+		BasePath:          cfg.Server.BasePath,
+		Versions:          skeletonServer.VersionOptions{Default: cfg.APIVersions.Default},
+		Tracer:            tracer,
+		Logger:            zlogger,
+		AccessLog: skeletonServer.AccessLogOptions{
+			SampleRate:   cfg.AccessLog.SampleRate,
+			ExcludePaths: cfg.AccessLog.ExcludePaths,
+		},
+		Timeouts: skeletonServer.TimeoutOptions{
+			Default: cfg.RequestTimeout.Default,
+		},
+		ServerTimeouts: grace.Timeouts{
+			Read:       cfg.Server.Timeouts.Read,
+			ReadHeader: cfg.Server.Timeouts.ReadHeader,
+			Write:      cfg.Server.Timeouts.Write,
+			Idle:       cfg.Server.Timeouts.Idle,
+			Drain:      cfg.Server.Timeouts.Drain,
+			Shutdown:   cfg.Server.Timeouts.Shutdown,
+		},
+	}
+	for _, vc := range cfg.APIVersions.Versions {
+		s.Versions.Versions = append(s.Versions.Versions, skeletonServer.APIVersion{
+			Name:       vc.Name,
+			Deprecated: vc.Deprecated,
+			Sunset:     vc.Sunset,
+		})
+	}
+	for _, rc := range cfg.RequestTimeout.Routes {
+		s.Timeouts.Routes = append(s.Timeouts.Routes, skeletonServer.TimeoutRoute{
+			PathPrefix: rc.PathPrefix,
+			Timeout:    rc.Timeout,
+		})
+	}
+
+	// CORS policy, origins are reloaded when they come from a file
+	origins := corspolicy.NewOrigins(cfg.CORS.AllowedOrigins)
+	if cfg.CORS.OriginsFile != "" {
+		if origins, err = corspolicy.LoadOrigins(cfg.CORS.OriginsFile); err != nil {
+			log.Fatalf("[CORS] Failed to load allowed origins: %v", err)
+		}
+	}
+	corsPolicy := corsPolicyFromConfig(cfg.CORS.CORSPolicyConfig)
+	corsPolicy.Origins = origins
+	var corsRoutes []corspolicy.Route
+	for _, rc := range cfg.CORS.Routes {
+		corsRoutes = append(corsRoutes, corspolicy.Route{
+			PathPrefix: rc.PathPrefix,
+			Policy:     corsPolicyFromConfig(rc.CORSPolicyConfig),
+		})
+	}
+	s.CORS = corspolicy.New(corsPolicy, corsRoutes...)
+
+	// Client IPs are read from X-Forwarded-For behind these proxies only
+	if s.TrustedProxies, err = trustedProxiesFromConfig(cfg.Server.TrustedProxies); err != nil {
+		log.Fatalf("[HTTP] Invalid trusted proxies: %v", err)
+	}
+
+	// Rate limits per principal, each replica keeps its own buckets
+	var rateRoutes []ratelimit.Route
+	for _, rc := range cfg.RateLimit.Routes {
+		rateRoutes = append(rateRoutes, ratelimit.Route{
+			PathPrefix: rc.PathPrefix,
+			Limit:      rateLimitFromConfig(rc.RateLimitQuota),
+		})
+	}
+	s.RateLimiter = ratelimit.New(ratelimit.NewMemoryStore(), rateLimitFromConfig(cfg.RateLimit.Default), rateRoutes...)
+	// and per client IP ahead of authentication, so bad credentials are limited too
+	s.IPRateLimiter = ratelimit.New(ratelimit.NewMemoryStore(), rateLimitFromConfig(cfg.RateLimit.IP))
+
+	// Dependencies checked by /readyz
+	s.Health = health.New(cfg.Health.Timeout)
+	s.Health.Register("mysql", db.PingContext)
+	s.Health.Register("auth_api", authAPI.Ping)
+
+	// Idempotency-Key replays
+	switch cfg.Idempotency.Store {
+	case "mysql":
+		s.Idempotency = idempotencyData.New(db, tracer, zlogger)
+	case "memory":
+		s.Idempotency = idempotencyData.NewMemory()
+	case "":
+	default:
+		log.Fatalf("[IDEMPOTENCY] Unknown store %q", cfg.Idempotency.Store)
+	}
+	s.IdempotencyTTL = cfg.Idempotency.TTL
+	s.IdempotencyMaxBody = cfg.Idempotency.MaxBodySize
+
+	if c := cfg.Compression; c.Enabled {
+		s.Compression = compression.New(compression.Options{
+			MinSize:      c.MinSize,
+			ContentTypes: c.ContentTypes,
+			Level:        c.Level,
+			MaxBodySize:  c.MaxBodySize,
+			ErrorHandler: skeletonServer.CompressionError,
+		})
+	}
+
+	// Serve HTTPS, and mutual TLS when a client CA is configured
+	if t := cfg.Server.TLS; t.CertFile != "" {
+		s.TLSConfig, err = tlsutil.NewServerConfig(tlsutil.ServerOptions{
+			CertFile:     t.CertFile,
+			KeyFile:      t.KeyFile,
+			ClientCAFile: t.ClientCAFile,
+			ClientAuth:   t.ClientAuth,
+			MinVersion:   t.MinVersion,
+			CipherPolicy: t.CipherPolicy,
+		})
+		if err != nil {
+			log.Fatalf("[TLS] Failed to initialize server TLS: %v", err)
+		}
 	}
 
 	if err := s.Serve(cfg.Server.Port); err != http.ErrServerClosed {
@@ -68,3 +238,44 @@ func HTTP() error {
 
 	return nil
 }
+
+// trustedProxiesFromConfig parses CIDRs and single addresses
+func trustedProxiesFromConfig(proxies []string) ([]netip.Prefix, error) {
+	prefixes := make([]netip.Prefix, 0, len(proxies))
+	for _, p := range proxies {
+		if !strings.Contains(p, "/") {
+			addr, err := netip.ParseAddr(p)
+			if err != nil {
+				return nil, err
+			}
+			prefixes = append(prefixes, netip.PrefixFrom(addr, addr.BitLen()))
+			continue
+		}
+		prefix, err := netip.ParsePrefix(p)
+		if err != nil {
+			return nil, err
+		}
+		prefixes = append(prefixes, prefix.Masked())
+	}
+	return prefixes, nil
+}
+
+// corsPolicyFromConfig converts a policy of the config,
+// a policy without origins inherits the default ones
+func corsPolicyFromConfig(c config.CORSPolicyConfig) corspolicy.Policy {
+	p := corspolicy.Policy{
+		AllowedMethods:   c.AllowedMethods,
+		AllowedHeaders:   c.AllowedHeaders,
+		ExposedHeaders:   c.ExposedHeaders,
+		AllowCredentials: c.AllowCredentials,
+		MaxAge:           c.MaxAge,
+	}
+	if len(c.AllowedOrigins) > 0 {
+		p.Origins = corspolicy.NewOrigins(c.AllowedOrigins)
+	}
+	return p
+}
+
+func rateLimitFromConfig(q config.RateLimitQuota) ratelimit.Limit {
+	return ratelimit.Limit{Requests: q.Requests, Per: q.Per, Burst: q.Burst}
+}
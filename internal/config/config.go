@@ -36,7 +36,17 @@ func Init(opts ...Option) error {
 		return err
 	}
 
-	return yaml.Unmarshal(out, &config)
+	if err = yaml.Unmarshal(out, &config); err != nil {
+		return err
+	}
+
+	if config.Authz.PolicyFile != "" && !filepath.IsAbs(config.Authz.PolicyFile) {
+		config.Authz.PolicyFile = filepath.Join(filepath.Dir(opt.configFile), config.Authz.PolicyFile)
+	}
+	if config.CORS.OriginsFile != "" && !filepath.IsAbs(config.CORS.OriginsFile) {
+		config.CORS.OriginsFile = filepath.Join(filepath.Dir(opt.configFile), config.CORS.OriginsFile)
+	}
+	return nil
 }
 
 // Option ...
@@ -1,17 +1,71 @@
 package config
 
+import "time"
+
 type (
 	// Config ...
 	Config struct {
-		Server   ServerConfig   `yaml:"server"`
-		Database DatabaseConfig `yaml:"database"`
-		API      APIConfig      `yaml:"api"`
-		Swagger  SwaggerConfig  `yaml:"swagger"`
+		Server         ServerConfig         `yaml:"server"`
+		Database       DatabaseConfig       `yaml:"database"`
+		API            APIConfig            `yaml:"api"`
+		Swagger        SwaggerConfig        `yaml:"swagger"`
+		Authz          AuthzConfig          `yaml:"authz"`
+		APIKey         APIKeyConfig         `yaml:"apikey"`
+		Revocation     RevocationConfig     `yaml:"revocation"`
+		AccessLog      AccessLogConfig      `yaml:"access_log"`
+		CORS           CORSConfig           `yaml:"cors"`
+		RateLimit      RateLimitConfig      `yaml:"rate_limit"`
+		Idempotency    IdempotencyConfig    `yaml:"idempotency"`
+		Compression    CompressionConfig    `yaml:"compression"`
+		RequestTimeout RequestTimeoutConfig `yaml:"request_timeout"`
+		APIVersions    APIVersionsConfig    `yaml:"api_versions"`
+		Health         HealthConfig         `yaml:"health"`
 	}
 
 	// ServerConfig ...
 	ServerConfig struct {
 		Port string `yaml:"port"`
+		// BasePath is the prefix of the API routes, /example when empty
+		BasePath string               `yaml:"base_path"`
+		TLS      ServerTLSConfig      `yaml:"tls"`
+		Timeouts ServerTimeoutsConfig `yaml:"timeouts"`
+		// TrustedProxies are the addresses or CIDRs of the proxies in front of the
+		// server, X-Forwarded-For is ignored on requests from any other peer
+		TrustedProxies []string `yaml:"trusted_proxies"`
+	}
+
+	// ServerTimeoutsConfig are the timeouts of the connections, see http.Server.
+	// Write must exceed the request timeouts.
+	ServerTimeoutsConfig struct {
+		Read       time.Duration `yaml:"read"`
+		ReadHeader time.Duration `yaml:"read_header"`
+		Write      time.Duration `yaml:"write"`
+		Idle       time.Duration `yaml:"idle"`
+		// Drain is how long the server keeps serving, not ready, after the shutdown signal
+		Drain time.Duration `yaml:"drain"`
+		// Shutdown is how long in-flight requests are waited for on shutdown
+		Shutdown time.Duration `yaml:"shutdown"`
+	}
+
+	// ServerTLSConfig enables HTTPS when CertFile is set and mutual TLS when ClientCAFile is set
+	ServerTLSConfig struct {
+		CertFile     string `yaml:"cert_file"`
+		KeyFile      string `yaml:"key_file"`
+		ClientCAFile string `yaml:"client_ca_file"`
+		// ClientAuth is none, request, require_any, verify_if_given or require_and_verify
+		ClientAuth string `yaml:"client_auth"`
+		MinVersion string `yaml:"min_version"`
+		// CipherPolicy is default or modern
+		CipherPolicy string `yaml:"cipher_policy"`
+	}
+
+	// ClientTLSConfig ...
+	ClientTLSConfig struct {
+		CertFile   string `yaml:"cert_file"`
+		KeyFile    string `yaml:"key_file"`
+		CAFile     string `yaml:"ca_file"`
+		ServerName string `yaml:"server_name"`
+		MinVersion string `yaml:"min_version"`
 	}
 
 	// DatabaseConfig ...
@@ -21,7 +75,150 @@ type (
 
 	// APIConfig ...
 	APIConfig struct {
-		Auth string `yaml:"auth"`
+		Auth    string          `yaml:"auth"`
+		AuthTLS ClientTLSConfig `yaml:"auth_tls"`
+		// AuthBatch checks several rights in one request to /checkrights/batch,
+		// only enable it when the auth API implements that endpoint
+		AuthBatch bool `yaml:"auth_batch"`
+	}
+
+	// AuthzConfig ...
+	AuthzConfig struct {
+		// PolicyFile is resolved relative to the config file when not absolute
+		PolicyFile string `yaml:"policy_file"`
+	}
+
+	// APIKeyConfig ...
+	APIKeyConfig struct {
+		// RotationOverlap is how long a rotated key stays valid, e.g. "24h"
+		RotationOverlap time.Duration `yaml:"rotation_overlap"`
+	}
+
+	// RevocationConfig ...
+	RevocationConfig struct {
+		// SyncInterval is how often revocations are reloaded from the database
+		SyncInterval time.Duration `yaml:"sync_interval"`
+		// MaxTokenLifetime is how long a revocation is kept when the token expiry is unknown
+		MaxTokenLifetime time.Duration `yaml:"max_token_lifetime"`
+	}
+
+	// AccessLogConfig ...
+	AccessLogConfig struct {
+		// SampleRate is the fraction of successful requests logged, failed ones are always logged.
+		// 0 logs every request.
+		SampleRate float64 `yaml:"sample_rate"`
+		// ExcludePaths are never logged, e.g. health checks
+		ExcludePaths []string `yaml:"exclude_paths"`
+	}
+
+	// CORSConfig ...
+	CORSConfig struct {
+		CORSPolicyConfig `yaml:",inline"`
+		// OriginsFile replaces AllowedOrigins with a YAML list that is reloaded when
+		// it changes, it is resolved relative to the config file when not absolute
+		OriginsFile string `yaml:"origins_file"`
+		// Routes override the policy for path prefixes
+		Routes []CORSRouteConfig `yaml:"routes"`
+	}
+
+	// CORSRouteConfig ...
+	CORSRouteConfig struct {
+		PathPrefix       string `yaml:"path_prefix"`
+		CORSPolicyConfig `yaml:",inline"`
+	}
+
+	// CORSPolicyConfig ...
+	CORSPolicyConfig struct {
+		// AllowedOrigins may contain wildcards, e.g. "https://*.example.com"
+		AllowedOrigins   []string      `yaml:"allowed_origins"`
+		AllowedMethods   []string      `yaml:"allowed_methods"`
+		AllowedHeaders   []string      `yaml:"allowed_headers"`
+		ExposedHeaders   []string      `yaml:"exposed_headers"`
+		AllowCredentials bool          `yaml:"allow_credentials"`
+		MaxAge           time.Duration `yaml:"max_age"`
+	}
+
+	// RateLimitConfig ...
+	RateLimitConfig struct {
+		// IP is the quota of each client IP, checked before authentication
+		IP      RateLimitQuota `yaml:"ip"`
+		Default RateLimitQuota `yaml:"default"`
+		// Routes override the default quota for path prefixes
+		Routes []RateLimitRouteConfig `yaml:"routes"`
+	}
+
+	// RateLimitRouteConfig ...
+	RateLimitRouteConfig struct {
+		PathPrefix     string `yaml:"path_prefix"`
+		RateLimitQuota `yaml:",inline"`
+	}
+
+	// RateLimitQuota allows Requests per Per period in bursts of up to Burst requests.
+	// 0 requests is unlimited, 0 burst is Requests.
+	RateLimitQuota struct {
+		Requests int           `yaml:"requests"`
+		Per      time.Duration `yaml:"per"`
+		Burst    int           `yaml:"burst"`
+	}
+
+	// IdempotencyConfig ...
+	IdempotencyConfig struct {
+		// Store is mysql, shared by the replicas, or memory
+		Store string `yaml:"store"`
+		// TTL is how long responses are replayed
+		TTL time.Duration `yaml:"ttl"`
+		// MaxBodySize limits the size in bytes of the request bodies kept to
+		// fingerprint requests, larger requests are rejected with 413
+		MaxBodySize int64 `yaml:"max_body_size"`
+	}
+
+	// CompressionConfig ...
+	CompressionConfig struct {
+		Enabled bool `yaml:"enabled"`
+		// MinSize is the size in bytes from which responses are compressed
+		MinSize int `yaml:"min_size"`
+		// ContentTypes are the media types compressed, e.g. "text/*"
+		ContentTypes []string `yaml:"content_types"`
+		// Level is the gzip level, from 1 (fastest) to 9 (best)
+		Level int `yaml:"level"`
+		// MaxBodySize limits the size in bytes of decompressed request bodies
+		MaxBodySize int64 `yaml:"max_body_size"`
+	}
+
+	// RequestTimeoutConfig are the deadlines given to the handlers, 0 is no deadline
+	RequestTimeoutConfig struct {
+		Default time.Duration `yaml:"default"`
+		// Routes override the default for path prefixes
+		Routes []RequestTimeoutRouteConfig `yaml:"routes"`
+	}
+
+	// RequestTimeoutRouteConfig ...
+	RequestTimeoutRouteConfig struct {
+		PathPrefix string        `yaml:"path_prefix"`
+		Timeout    time.Duration `yaml:"timeout"`
+	}
+
+	// APIVersionsConfig ...
+	APIVersionsConfig struct {
+		// Default serves the unversioned paths requested without Accept-Version
+		Default string `yaml:"default"`
+		// Versions are the served versions, oldest first
+		Versions []APIVersionConfig `yaml:"versions"`
+	}
+
+	// APIVersionConfig ...
+	APIVersionConfig struct {
+		Name string `yaml:"name"`
+		// Deprecated and Sunset are RFC 3339 times, they are advertised in the
+		// Deprecation and Sunset headers when set
+		Deprecated time.Time `yaml:"deprecated"`
+		Sunset     time.Time `yaml:"sunset"`
+	}
+
+	// HealthConfig ...
+	HealthConfig struct {
+		// Timeout is the time given to each dependency check of /readyz
+		Timeout time.Duration `yaml:"timeout"`
 	}
 
 	SwaggerConfig struct {
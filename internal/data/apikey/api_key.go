@@ -0,0 +1,86 @@
+package apikey
+
+import (
+	"context"
+	"time"
+
+	"go-skeleton-auth/internal/entity/apikey"
+	"go-skeleton-auth/pkg/errors"
+
+	"go.uber.org/zap"
+)
+
+// GetAPIKeyByHash ...
+func (d Data) GetAPIKeyByHash(ctx context.Context, keyHash string) (apikey.APIKey, error) {
+	var key apikey.APIKey
+
+	ctx, finish := d.startSpan(ctx, "SQL SELECT", qGetAPIKeyByHash)
+	defer finish()
+
+	if err := d.stmt[getAPIKeyByHash].GetContext(ctx, &key, keyHash); err != nil {
+		return key, errors.Wrap(err, "[DATA][GetAPIKeyByHash]")
+	}
+	return key, nil
+}
+
+// GetAPIKeyByID ...
+func (d Data) GetAPIKeyByID(ctx context.Context, id int64) (apikey.APIKey, error) {
+	var key apikey.APIKey
+
+	ctx, finish := d.startSpan(ctx, "SQL SELECT", qGetAPIKeyByID)
+	defer finish()
+
+	if err := d.stmt[getAPIKeyByID].GetContext(ctx, &key, id); err != nil {
+		return key, errors.Wrap(err, "[DATA][GetAPIKeyByID]")
+	}
+	return key, nil
+}
+
+// InsertAPIKey inserts key and returns its ID
+func (d Data) InsertAPIKey(ctx context.Context, key apikey.APIKey) (int64, error) {
+	ctx, finish := d.startSpan(ctx, "SQL INSERT", qInsertAPIKey)
+	defer finish()
+
+	res, err := d.stmt[insertAPIKey].ExecContext(ctx,
+		key.KeyHash,
+		key.Prefix,
+		key.Owner,
+		key.Scopes,
+		key.CreatedAt,
+		key.ExpiresAt,
+	)
+	if err != nil {
+		d.logger.For(ctx).Error("SQL Query Failed", zap.Error(err))
+		return 0, errors.Wrap(err, "[DATA][InsertAPIKey]")
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return 0, errors.Wrap(err, "[DATA][InsertAPIKey]")
+	}
+	return id, nil
+}
+
+// UpdateAPIKeyExpiry ...
+func (d Data) UpdateAPIKeyExpiry(ctx context.Context, id int64, expiresAt time.Time) error {
+	ctx, finish := d.startSpan(ctx, "SQL UPDATE", qUpdateAPIKeyExpiry)
+	defer finish()
+
+	if _, err := d.stmt[updateAPIKeyExpiry].ExecContext(ctx, expiresAt, id); err != nil {
+		d.logger.For(ctx).Error("SQL Query Failed", zap.Error(err))
+		return errors.Wrap(err, "[DATA][UpdateAPIKeyExpiry]")
+	}
+	return nil
+}
+
+// UpdateAPIKeyLastUsed ...
+func (d Data) UpdateAPIKeyLastUsed(ctx context.Context, id int64, lastUsedAt time.Time) error {
+	ctx, finish := d.startSpan(ctx, "SQL UPDATE", qUpdateAPIKeyLastUsed)
+	defer finish()
+
+	if _, err := d.stmt[updateAPIKeyLastUsed].ExecContext(ctx, lastUsedAt, id); err != nil {
+		d.logger.For(ctx).Error("SQL Query Failed", zap.Error(err))
+		return errors.Wrap(err, "[DATA][UpdateAPIKeyLastUsed]")
+	}
+	return nil
+}
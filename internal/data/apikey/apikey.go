@@ -0,0 +1,118 @@
+package apikey
+
+import (
+	"context"
+	"log"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/opentracing/opentracing-go"
+
+	jaegerLog "go-skeleton-auth/pkg/log"
+)
+
+type (
+	// Data ...
+	Data struct {
+		db   *sqlx.DB
+		stmt map[string]*sqlx.Stmt
+
+		tracer opentracing.Tracer
+		logger jaegerLog.Factory
+	}
+
+	// statement ...
+	statement struct {
+		key   string
+		query string
+	}
+)
+
+// Table definition is in files/sql/api_keys.sql
+const (
+	getAPIKeyByHash  = "GetAPIKeyByHash"
+	qGetAPIKeyByHash = `SELECT id, key_hash, prefix, owner, scopes, created_at, expires_at, last_used_at
+		FROM api_keys WHERE key_hash = ?`
+
+	getAPIKeyByID  = "GetAPIKeyByID"
+	qGetAPIKeyByID = `SELECT id, key_hash, prefix, owner, scopes, created_at, expires_at, last_used_at
+		FROM api_keys WHERE id = ?`
+
+	insertAPIKey  = "InsertAPIKey"
+	qInsertAPIKey = `INSERT INTO api_keys (key_hash, prefix, owner, scopes, created_at, expires_at)
+		VALUES (?, ?, ?, ?, ?, ?)`
+
+	updateAPIKeyExpiry  = "UpdateAPIKeyExpiry"
+	qUpdateAPIKeyExpiry = "UPDATE api_keys SET expires_at = ? WHERE id = ?"
+
+	updateAPIKeyLastUsed  = "UpdateAPIKeyLastUsed"
+	qUpdateAPIKeyLastUsed = "UPDATE api_keys SET last_used_at = ? WHERE id = ?"
+)
+
+var (
+	readStmt = []statement{
+		{getAPIKeyByHash, qGetAPIKeyByHash},
+		{getAPIKeyByID, qGetAPIKeyByID},
+	}
+	insertStmt = []statement{
+		{insertAPIKey, qInsertAPIKey},
+	}
+	updateStmt = []statement{
+		{updateAPIKeyExpiry, qUpdateAPIKeyExpiry},
+		{updateAPIKeyLastUsed, qUpdateAPIKeyLastUsed},
+	}
+)
+
+// New ...
+func New(db *sqlx.DB, tracer opentracing.Tracer, logger jaegerLog.Factory) Data {
+	d := Data{
+		db:     db,
+		tracer: tracer,
+		logger: logger,
+	}
+
+	d.initStmt()
+	return d
+}
+
+func (d *Data) initStmt() {
+	var (
+		err   error
+		stmts = make(map[string]*sqlx.Stmt)
+	)
+
+	for _, v := range readStmt {
+		stmts[v.key], err = d.db.PreparexContext(context.Background(), v.query)
+		if err != nil {
+			log.Fatalf("[DB] Failed to initialize select statement key %v, err : %v", v.key, err)
+		}
+	}
+
+	for _, v := range insertStmt {
+		stmts[v.key], err = d.db.PreparexContext(context.Background(), v.query)
+		if err != nil {
+			log.Fatalf("[DB] Failed to initialize insert statement key %v, err : %v", v.key, err)
+		}
+	}
+
+	for _, v := range updateStmt {
+		stmts[v.key], err = d.db.PreparexContext(context.Background(), v.query)
+		if err != nil {
+			log.Fatalf("[DB] Failed to initialize update statement key %v, err : %v", v.key, err)
+		}
+	}
+
+	d.stmt = stmts
+}
+
+// startSpan starts a child span for a query when ctx carries a span
+func (d Data) startSpan(ctx context.Context, operation, query string) (context.Context, func()) {
+	span := opentracing.SpanFromContext(ctx)
+	if span == nil {
+		return ctx, func() {}
+	}
+
+	span = d.tracer.StartSpan(operation, opentracing.ChildOf(span.Context()))
+	span.SetTag("mysql.table", "api_keys")
+	span.SetTag("mysql.query", query)
+	return opentracing.ContextWithSpan(ctx, span), span.Finish
+}
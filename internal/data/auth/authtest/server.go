@@ -0,0 +1,136 @@
+// Package authtest provides a fake auth API for tests
+package authtest
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+
+	"go-skeleton-auth/internal/entity/auth"
+)
+
+// Server is a fake auth API serving /checkrights and /checkrights/batch.
+// Tokens are matched against the full Authorization header value.
+type Server struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	rights   map[string]map[string]bool
+	status   int
+	requests int
+}
+
+// NewServer starts a fake auth API, call Close when done
+func NewServer() *Server {
+	s := &Server{
+		rights: make(map[string]map[string]bool),
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/checkrights", s.checkRights)
+	mux.HandleFunc("/checkrights/batch", s.checkRightsBatch)
+	s.Server = httptest.NewServer(mux)
+	return s
+}
+
+// Grant gives the right codes to token
+func (s *Server) Grant(token string, codes ...string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.rights[token] == nil {
+		s.rights[token] = make(map[string]bool)
+	}
+	for _, code := range codes {
+		s.rights[token][code] = true
+	}
+}
+
+// FailWith makes every following request fail with the given HTTP status
+// and a non JSON body, 0 restores normal behaviour
+func (s *Server) FailWith(status int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.status = status
+}
+
+// Requests returns the number of requests served
+func (s *Server) Requests() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.requests
+}
+
+// authorize returns the rights of the request token, false when it fails or has no rights
+func (s *Server) authorize(w http.ResponseWriter, r *http.Request) (map[string]bool, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.requests++
+	if s.status != 0 {
+		http.Error(w, http.StatusText(s.status), s.status)
+		return nil, false
+	}
+
+	rights, ok := s.rights[r.Header.Get("Authorization")]
+	if !ok {
+		render(w, http.StatusUnauthorized, auth.Auth{
+			Error: auth.Err{Status: true, Msg: "invalid token", Code: http.StatusUnauthorized},
+		})
+		return nil, false
+	}
+	return rights, true
+}
+
+func (s *Server) checkRights(w http.ResponseWriter, r *http.Request) {
+	var req auth.CheckRightsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	rights, ok := s.authorize(w, r)
+	if !ok {
+		return
+	}
+	render(w, http.StatusOK, decision(rights[req.Code]))
+}
+
+func (s *Server) checkRightsBatch(w http.ResponseWriter, r *http.Request) {
+	var req auth.CheckRightsBatchRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	rights, ok := s.authorize(w, r)
+	if !ok {
+		return
+	}
+	batch := auth.BatchAuth{Data: make(map[string]auth.Auth, len(req.Codes))}
+	for _, code := range req.Codes {
+		batch.Data[code] = decision(rights[code])
+	}
+	render(w, http.StatusOK, batch)
+}
+
+func decision(allowed bool) auth.Auth {
+	if !allowed {
+		return auth.Auth{
+			Error: auth.Err{Status: true, Msg: "forbidden", Code: http.StatusForbidden},
+		}
+	}
+	return auth.Auth{
+		Data:     auth.UserID{UserID: 1},
+		Metadata: auth.Metadata{Status: true},
+	}
+}
+
+func render(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
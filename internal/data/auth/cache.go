@@ -0,0 +1,346 @@
+package auth
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"go-skeleton-auth/internal/entity/auth"
+	"go-skeleton-auth/pkg/errors"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+const (
+	defaultAllowTTL   = time.Minute
+	defaultDenyTTL    = 10 * time.Second
+	defaultMaxEntries = 10000
+	// defaultCallTimeout bounds a shared upstream call,
+	// it no longer depends on the deadline of any caller
+	defaultCallTimeout = 15 * time.Second
+)
+
+// Checker is the remote rights check implemented by Data
+type Checker interface {
+	CheckAuth(ctx context.Context, _token, code string) (auth.Auth, error)
+	CheckAuthBatch(ctx context.Context, _token string, codes []string) (map[string]auth.Auth, error)
+}
+
+// CachedData decorates a Checker with a decision cache.
+// Decisions are cached per token and code, never beyond the token expiry,
+// and concurrent identical checks share a single upstream request.
+// Several codes are checked with one request per code unless batching is
+// enabled, the batch endpoint is not part of every auth API.
+// When the auth API is unavailable, e.g. its circuit breaker is open, decisions
+// that are stale but still within the token expiry are served, otherwise
+// an error of kind ErrUpstreamUnavailable is returned.
+type CachedData struct {
+	checker Checker
+
+	allowTTL   time.Duration
+	denyTTL    time.Duration
+	maxEntries int
+	batch      bool
+	// callTimeout bounds a shared upstream call
+	callTimeout time.Duration
+
+	mu      sync.Mutex
+	entries map[string]cacheEntry
+	calls   map[string]*call
+
+	now func() time.Time
+}
+
+type cacheEntry struct {
+	auth       auth.Auth
+	freshUntil time.Time
+	// expireAt is the token expiry, stale entries are kept until then
+	expireAt time.Time
+}
+
+// call is an in-flight upstream request shared by concurrent callers
+type call struct {
+	// done is closed when the call returns
+	done chan struct{}
+	val  map[string]auth.Auth
+	err  error
+}
+
+// CacheOption ...
+type CacheOption func(*CachedData)
+
+// WithAllowTTL sets how long allow decisions are cached
+func WithAllowTTL(ttl time.Duration) CacheOption {
+	return func(c *CachedData) {
+		c.allowTTL = ttl
+	}
+}
+
+// WithDenyTTL sets how long deny decisions are cached
+func WithDenyTTL(ttl time.Duration) CacheOption {
+	return func(c *CachedData) {
+		c.denyTTL = ttl
+	}
+}
+
+// WithMaxEntries bounds the number of cached decisions
+func WithMaxEntries(n int) CacheOption {
+	return func(c *CachedData) {
+		c.maxEntries = n
+	}
+}
+
+// WithBatch sends the codes missing from the cache in one request
+// to /checkrights/batch instead of one request per code to /checkrights
+func WithBatch(enabled bool) CacheOption {
+	return func(c *CachedData) {
+		c.batch = enabled
+	}
+}
+
+// WithCallTimeout bounds the upstream requests shared by concurrent callers
+func WithCallTimeout(timeout time.Duration) CacheOption {
+	return func(c *CachedData) {
+		c.callTimeout = timeout
+	}
+}
+
+// NewCached ...
+func NewCached(checker Checker, opts ...CacheOption) *CachedData {
+	c := &CachedData{
+		checker:     checker,
+		allowTTL:    defaultAllowTTL,
+		denyTTL:     defaultDenyTTL,
+		maxEntries:  defaultMaxEntries,
+		callTimeout: defaultCallTimeout,
+		entries:     make(map[string]cacheEntry),
+		calls:       make(map[string]*call),
+		now:         time.Now,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// CheckAuth checks a single right code, see Data.CheckAuth
+func (c *CachedData) CheckAuth(ctx context.Context, _token, code string) (auth.Auth, error) {
+	result, err := c.CheckAuthBatch(ctx, _token, []string{code})
+	if err != nil {
+		return auth.Auth{}, err
+	}
+	if err = decisionError(result[code]); err != nil {
+		return result[code], errors.Wrap(err, "[DATA][CheckAuth]")
+	}
+	return result[code], nil
+}
+
+// CheckAuthBatch checks several codes, only codes without a fresh
+// cached decision are sent upstream
+func (c *CachedData) CheckAuthBatch(ctx context.Context, _token string, codes []string) (map[string]auth.Auth, error) {
+	var (
+		result  = make(map[string]auth.Auth, len(codes))
+		missing []string
+		now     = c.now()
+	)
+
+	c.mu.Lock()
+	for _, code := range codes {
+		if e, ok := c.entries[cacheKey(_token, code)]; ok && now.Before(e.freshUntil) {
+			result[code] = e.auth
+			continue
+		}
+		missing = append(missing, code)
+	}
+	c.mu.Unlock()
+
+	if len(missing) == 0 {
+		return result, nil
+	}
+
+	fetched, err := c.fetch(ctx, _token, missing)
+	if err != nil {
+		if !errors.Is(err, ErrUpstreamUnavailable) {
+			return nil, err
+		}
+		return c.stale(_token, missing, result)
+	}
+
+	for _, code := range missing {
+		result[code] = fetched[code]
+	}
+	return result, nil
+}
+
+// fetch requests codes upstream, coalescing identical concurrent requests.
+// The request runs detached from the caller that started it, every caller
+// including that one stops waiting at its own deadline.
+func (c *CachedData) fetch(ctx context.Context, _token string, codes []string) (map[string]auth.Auth, error) {
+	sorted := append([]string{}, codes...)
+	sort.Strings(sorted)
+	key := cacheKey(_token, strings.Join(sorted, ","))
+
+	c.mu.Lock()
+	cl, ok := c.calls[key]
+	if !ok {
+		cl = &call{done: make(chan struct{})}
+		c.calls[key] = cl
+		go c.do(detach(ctx), key, _token, sorted, cl)
+	}
+	c.mu.Unlock()
+
+	select {
+	case <-cl.done:
+		return cl.val, cl.err
+	case <-ctx.Done():
+		return nil, unavailable(0, ctx.Err())
+	}
+}
+
+// do performs the shared call cl
+func (c *CachedData) do(ctx context.Context, key, _token string, codes []string, cl *call) {
+	ctx, cancel := context.WithTimeout(ctx, c.callTimeout)
+	defer cancel()
+
+	cl.val, cl.err = c.checkUpstream(ctx, _token, codes)
+	if cl.err == nil {
+		c.store(_token, cl.val)
+	}
+
+	c.mu.Lock()
+	delete(c.calls, key)
+	c.mu.Unlock()
+	close(cl.done)
+}
+
+func (c *CachedData) checkUpstream(ctx context.Context, _token string, codes []string) (map[string]auth.Auth, error) {
+	if len(codes) == 1 || !c.batch {
+		result := make(map[string]auth.Auth, len(codes))
+		for _, code := range codes {
+			a, err := c.checker.CheckAuth(ctx, _token, code)
+			// denials are decisions to cache, not failures
+			if err != nil && !errors.Is(err, ErrForbidden) && !errors.Is(err, ErrUnauthorized) {
+				return nil, err
+			}
+			result[code] = a
+		}
+		return result, nil
+	}
+
+	result, err := c.checker.CheckAuthBatch(ctx, _token, codes)
+	if err != nil {
+		return nil, err
+	}
+	for _, code := range codes {
+		// a code missing from the batch response is treated as denied
+		if _, ok := result[code]; !ok {
+			result[code] = auth.Auth{
+				Error: auth.Err{Status: true, Msg: "right not found", Code: http.StatusForbidden},
+			}
+		}
+	}
+	return result, nil
+}
+
+func (c *CachedData) store(_token string, decisions map[string]auth.Auth) {
+	now := c.now()
+	expireAt, ok := tokenExpiry(_token)
+	if !ok {
+		expireAt = now.Add(c.allowTTL)
+	}
+	if !now.Before(expireAt) {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if len(c.entries)+len(decisions) > c.maxEntries {
+		c.evict(now)
+	}
+
+	for code, a := range decisions {
+		ttl := c.denyTTL
+		if a.Allowed() {
+			ttl = c.allowTTL
+		}
+		freshUntil := now.Add(ttl)
+		if freshUntil.After(expireAt) {
+			freshUntil = expireAt
+		}
+		c.entries[cacheKey(_token, code)] = cacheEntry{
+			auth:       a,
+			freshUntil: freshUntil,
+			expireAt:   expireAt,
+		}
+	}
+}
+
+// evict drops expired entries, and everything when that is not enough.
+// It must be called with c.mu held.
+func (c *CachedData) evict(now time.Time) {
+	for key, e := range c.entries {
+		if !now.Before(e.expireAt) {
+			delete(c.entries, key)
+		}
+	}
+	if len(c.entries) >= c.maxEntries {
+		c.entries = make(map[string]cacheEntry)
+	}
+}
+
+// stale serves decisions past their TTL but within the token expiry
+func (c *CachedData) stale(_token string, codes []string, result map[string]auth.Auth) (map[string]auth.Auth, error) {
+	now := c.now()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, code := range codes {
+		e, ok := c.entries[cacheKey(_token, code)]
+		if !ok || !now.Before(e.expireAt) {
+			return nil, errors.Wrap(&Error{Kind: ErrUpstreamUnavailable, Msg: "no cached decision"}, "[DATA][CheckAuth]")
+		}
+		result[code] = e.auth
+	}
+	return result, nil
+}
+
+func cacheKey(_token, code string) string {
+	return _token + "\x00" + code
+}
+
+// tokenExpiry reads the exp claim of a JWT without verifying it,
+// the signature has already been verified by the middleware
+func tokenExpiry(_token string) (time.Time, bool) {
+	_token = strings.TrimPrefix(_token, "Bearer ")
+
+	claims := jwt.MapClaims{}
+	if _, _, err := new(jwt.Parser).ParseUnverified(_token, claims); err != nil {
+		return time.Time{}, false
+	}
+	exp, ok := claims["exp"].(float64)
+	if !ok {
+		return time.Time{}, false
+	}
+	return time.Unix(int64(exp), 0), true
+}
+
+// detachedContext keeps the values of its parent, e.g. the span,
+// but is never canceled with it
+type detachedContext struct {
+	parent context.Context
+}
+
+func detach(ctx context.Context) context.Context {
+	return detachedContext{parent: ctx}
+}
+
+func (detachedContext) Deadline() (time.Time, bool) { return time.Time{}, false }
+func (detachedContext) Done() <-chan struct{}       { return nil }
+func (detachedContext) Err() error                  { return nil }
+
+func (d detachedContext) Value(key interface{}) interface{} { return d.parent.Value(key) }
@@ -0,0 +1,192 @@
+package auth
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go-skeleton-auth/internal/entity/auth"
+	"go-skeleton-auth/pkg/errors"
+
+	"github.com/afex/hystrix-go/hystrix"
+	"github.com/dgrijalva/jwt-go"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeChecker struct {
+	calls   int32
+	batches int32
+	delay   time.Duration
+	err     error
+	denied  map[string]bool
+}
+
+func (f *fakeChecker) decision(code string) auth.Auth {
+	if f.denied[code] {
+		return auth.Auth{Error: auth.Err{Status: true, Code: 403}}
+	}
+	return auth.Auth{}
+}
+
+func (f *fakeChecker) CheckAuth(ctx context.Context, _token, code string) (auth.Auth, error) {
+	atomic.AddInt32(&f.calls, 1)
+	time.Sleep(f.delay)
+	if f.err != nil {
+		return auth.Auth{}, f.err
+	}
+	a := f.decision(code)
+	return a, decisionError(a)
+}
+
+func (f *fakeChecker) CheckAuthBatch(ctx context.Context, _token string, codes []string) (map[string]auth.Auth, error) {
+	atomic.AddInt32(&f.batches, 1)
+	if f.err != nil {
+		return nil, f.err
+	}
+	result := make(map[string]auth.Auth, len(codes))
+	for _, code := range codes {
+		result[code] = f.decision(code)
+	}
+	return result, nil
+}
+
+func testToken(t *testing.T, exp time.Time) string {
+	s, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"exp": exp.Unix()}).SignedString([]byte("secret"))
+	require.NoError(t, err)
+	return "Bearer " + s
+}
+
+func TestCachedCheckAuth(t *testing.T) {
+	now := time.Now()
+	fc := &fakeChecker{denied: map[string]bool{"B": true}}
+	c := NewCached(fc, WithAllowTTL(time.Minute), WithDenyTTL(time.Second))
+	c.now = func() time.Time { return now }
+	token := testToken(t, now.Add(time.Hour))
+
+	a, err := c.CheckAuth(context.Background(), token, "A")
+	require.NoError(t, err)
+	require.True(t, a.Allowed())
+	_, err = c.CheckAuth(context.Background(), token, "A")
+	require.NoError(t, err)
+	require.Equal(t, int32(1), fc.calls)
+
+	b, err := c.CheckAuth(context.Background(), token, "B")
+	require.Equal(t, ErrForbidden, errors.Cause(err))
+	require.False(t, b.Allowed())
+
+	// deny decisions expire sooner than allow decisions
+	now = now.Add(2 * time.Second)
+	_, err = c.CheckAuth(context.Background(), token, "A")
+	require.NoError(t, err)
+	_, err = c.CheckAuth(context.Background(), token, "B")
+	require.Error(t, err)
+	require.Equal(t, int32(3), fc.calls)
+}
+
+func TestCachedCheckAuthTokenExpiry(t *testing.T) {
+	now := time.Now()
+	fc := &fakeChecker{}
+	c := NewCached(fc, WithAllowTTL(time.Hour))
+	c.now = func() time.Time { return now }
+	token := testToken(t, now.Add(time.Minute))
+
+	_, err := c.CheckAuth(context.Background(), token, "A")
+	require.NoError(t, err)
+
+	now = now.Add(2 * time.Minute)
+	_, err = c.CheckAuth(context.Background(), token, "A")
+	require.NoError(t, err)
+	require.Equal(t, int32(2), fc.calls)
+}
+
+func TestCachedCheckAuthSingleflight(t *testing.T) {
+	fc := &fakeChecker{delay: 50 * time.Millisecond}
+	c := NewCached(fc)
+	token := testToken(t, time.Now().Add(time.Hour))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := c.CheckAuth(context.Background(), token, "A")
+			require.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+	require.Equal(t, int32(1), fc.calls)
+}
+
+func TestCachedCheckAuthBatch(t *testing.T) {
+	fc := &fakeChecker{denied: map[string]bool{"C": true}}
+	c := NewCached(fc, WithBatch(true))
+	token := testToken(t, time.Now().Add(time.Hour))
+
+	_, err := c.CheckAuth(context.Background(), token, "A")
+	require.NoError(t, err)
+
+	result, err := c.CheckAuthBatch(context.Background(), token, []string{"A", "B", "C"})
+	require.NoError(t, err)
+	require.Len(t, result, 3)
+	require.True(t, result["A"].Allowed())
+	require.True(t, result["B"].Allowed())
+	require.False(t, result["C"].Allowed())
+	require.Equal(t, int32(1), fc.calls)
+	require.Equal(t, int32(1), fc.batches)
+}
+
+func TestCachedCheckAuthWithoutBatch(t *testing.T) {
+	fc := &fakeChecker{denied: map[string]bool{"C": true}}
+	c := NewCached(fc)
+	token := testToken(t, time.Now().Add(time.Hour))
+
+	result, err := c.CheckAuthBatch(context.Background(), token, []string{"A", "B", "C"})
+	require.NoError(t, err)
+	require.True(t, result["A"].Allowed())
+	require.True(t, result["B"].Allowed())
+	require.False(t, result["C"].Allowed())
+	require.Equal(t, int32(3), fc.calls)
+	require.Equal(t, int32(0), fc.batches)
+}
+
+func TestCachedCheckAuthLeaderCanceled(t *testing.T) {
+	fc := &fakeChecker{delay: 50 * time.Millisecond}
+	c := NewCached(fc)
+	token := testToken(t, time.Now().Add(time.Hour))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err := c.CheckAuth(ctx, token, "A")
+	require.Equal(t, ErrUpstreamUnavailable, errors.Cause(err))
+
+	// the shared call outlives the caller that started it
+	a, err := c.CheckAuth(context.Background(), token, "A")
+	require.NoError(t, err)
+	require.True(t, a.Allowed())
+	require.Equal(t, int32(1), fc.calls)
+}
+
+func TestCachedCheckAuthCircuitOpen(t *testing.T) {
+	now := time.Now()
+	fc := &fakeChecker{}
+	c := NewCached(fc, WithAllowTTL(time.Second))
+	c.now = func() time.Time { return now }
+	token := testToken(t, now.Add(time.Hour))
+
+	_, err := c.CheckAuth(context.Background(), token, "A")
+	require.NoError(t, err)
+
+	fc.err = errors.Wrap(unavailable(0, hystrix.ErrCircuitOpen), "[DATA][CheckAuth]")
+	now = now.Add(time.Minute)
+
+	// stale decision within the token expiry is served
+	a, err := c.CheckAuth(context.Background(), token, "A")
+	require.NoError(t, err)
+	require.True(t, a.Allowed())
+
+	// no cached decision at all
+	_, err = c.CheckAuth(context.Background(), token, "B")
+	require.Equal(t, ErrUpstreamUnavailable, errors.Cause(err))
+}
@@ -23,23 +23,60 @@ func New(client *httpclient.Client, baseURL string) Data {
 	return d
 }
 
-// CheckAuth ...
+// CheckAuth checks a single right code.
+// A denied right is returned as an Error of kind ErrForbidden or ErrUnauthorized
+// together with the response.
 func (d Data) CheckAuth(ctx context.Context, _token, code string) (auth.Auth, error) {
-	var auth auth.Auth
+	var rights auth.Auth
 	var endpoint = "/checkrights"
 	var url = d.baseURL + endpoint
-	var body = map[string]interface{}{
-		"code": code,
+	var body = auth.CheckRightsRequest{
+		Code: code,
 	}
 
+	resp, err := d.client.PostJSON(ctx, url, endpoint, d.headers(_token), body, &rights)
+	if err = d.checkError(resp, err, &rights.Error); err != nil {
+		return rights, errors.Wrap(err, "[DATA][CheckAuth]")
+	}
+
+	return rights, nil
+}
+
+// CheckAuthBatch checks several right codes in one request.
+// Denied rights are reported in their own error envelope, the returned error
+// is only set when the request as a whole failed.
+func (d Data) CheckAuthBatch(ctx context.Context, _token string, codes []string) (map[string]auth.Auth, error) {
+	var batch auth.BatchAuth
+	var endpoint = "/checkrights/batch"
+	var url = d.baseURL + endpoint
+	var body = auth.CheckRightsBatchRequest{
+		Codes: codes,
+	}
+
+	resp, err := d.client.PostJSON(ctx, url, endpoint, d.headers(_token), body, &batch)
+	if err = d.checkError(resp, err, &batch.Error); err != nil {
+		return nil, errors.Wrap(err, "[DATA][CheckAuthBatch]")
+	}
+
+	return batch.Data, nil
+}
+
+func (d Data) headers(_token string) http.Header {
 	headers := make(http.Header)
 	headers.Set("Authorization", _token)
 	headers.Set("Content-Type", "application/json")
+	return headers
+}
 
-	_, err := d.client.PostJSON(ctx, url, endpoint, headers, body, &auth)
-	if err != nil {
-		return auth, errors.Wrap(err, "[DATA][CheckAuth]")
+// checkError classifies the outcome of a request to the auth API
+func (d Data) checkError(resp *http.Response, err error, envelope *auth.Err) error {
+	// no response at all: connection failure, timeout or open circuit
+	if resp == nil {
+		return unavailable(0, err)
 	}
-
-	return auth, nil
+	// a successful response that cannot be decoded
+	if err != nil && resp.StatusCode < http.StatusBadRequest {
+		return unavailable(resp.StatusCode, err)
+	}
+	return checkResponse(resp.StatusCode, envelope)
 }
@@ -0,0 +1,101 @@
+package auth
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"go-skeleton-auth/internal/data/auth/authtest"
+	"go-skeleton-auth/pkg/errors"
+	"go-skeleton-auth/pkg/httpclient"
+
+	"github.com/opentracing/opentracing-go"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckAuth(t *testing.T) {
+	server := authtest.NewServer()
+	defer server.Close()
+	server.Grant("Bearer valid", "A")
+
+	d := New(httpclient.NewClient(opentracing.NoopTracer{}), server.URL)
+
+	testCases := []struct {
+		name   string
+		token  string
+		code   string
+		status int
+		kind   error
+	}{
+		{
+			name:  "granted",
+			token: "Bearer valid",
+			code:  "A",
+		},
+		{
+			name:  "forbidden",
+			token: "Bearer valid",
+			code:  "B",
+			kind:  ErrForbidden,
+		},
+		{
+			name:  "unauthorized",
+			token: "Bearer invalid",
+			code:  "A",
+			kind:  ErrUnauthorized,
+		},
+		{
+			name:   "upstream error",
+			token:  "Bearer valid",
+			code:   "A",
+			status: http.StatusBadGateway,
+			kind:   ErrUpstreamUnavailable,
+		},
+		{
+			name:   "upstream rejects without envelope",
+			token:  "Bearer valid",
+			code:   "A",
+			status: http.StatusForbidden,
+			kind:   ErrForbidden,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			server.FailWith(tc.status)
+
+			a, err := d.CheckAuth(context.Background(), tc.token, tc.code)
+			if tc.kind == nil {
+				require.NoError(t, err)
+				require.True(t, a.Allowed())
+				require.Equal(t, 1, a.Data.UserID)
+				return
+			}
+			require.Equal(t, tc.kind, errors.Cause(err))
+			require.True(t, errors.Is(err, tc.kind))
+			var authErr *Error
+			require.True(t, errors.As(err, &authErr))
+			if tc.kind != ErrUpstreamUnavailable {
+				require.False(t, a.Allowed())
+			}
+		})
+	}
+}
+
+func TestCheckAuthBatch(t *testing.T) {
+	server := authtest.NewServer()
+	defer server.Close()
+	server.Grant("Bearer valid", "A", "B")
+
+	d := New(httpclient.NewClient(opentracing.NoopTracer{}), server.URL)
+
+	result, err := d.CheckAuthBatch(context.Background(), "Bearer valid", []string{"A", "B", "C"})
+	require.NoError(t, err)
+	require.True(t, result["A"].Allowed())
+	require.True(t, result["B"].Allowed())
+	require.False(t, result["C"].Allowed())
+	require.Equal(t, 1, server.Requests())
+
+	_, err = d.CheckAuthBatch(context.Background(), "Bearer invalid", []string{"A"})
+	require.Equal(t, ErrUnauthorized, errors.Cause(err))
+}
@@ -0,0 +1,107 @@
+package auth
+
+import (
+	"fmt"
+	"net/http"
+
+	"go-skeleton-auth/internal/entity/auth"
+	"go-skeleton-auth/internal/entity/errcode"
+	"go-skeleton-auth/pkg/errors"
+)
+
+// Kinds of auth API errors, compare them with errors.Is
+var (
+	ErrUnauthorized        = errors.New("unauthorized")
+	ErrForbidden           = errors.New("forbidden")
+	ErrUpstreamUnavailable = errors.New("auth API unavailable")
+)
+
+// Error is a failure reported by the auth API
+type Error struct {
+	// Kind is one of ErrUnauthorized, ErrForbidden or ErrUpstreamUnavailable
+	Kind error
+	// StatusCode is the HTTP status of the response, 0 when there was none
+	StatusCode int
+	// Code is the application code of the error envelope
+	Code int
+	Msg  string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%v (status %d, code %d): %s", e.Kind, e.StatusCode, e.Code, e.Msg)
+}
+
+// Cause returns the kind of the error
+func (e *Error) Cause() error { return e.Kind }
+
+// Unwrap returns the kind of the error
+func (e *Error) Unwrap() error { return e.Kind }
+
+// Is reports whether target is the kind of the error
+func (e *Error) Is(target error) bool { return target == e.Kind }
+
+// ErrorCode returns the application error code of the kind of the error
+func (e *Error) ErrorCode() errors.Code {
+	switch e.Kind {
+	case ErrUnauthorized:
+		return errcode.Unauthorized
+	case ErrForbidden:
+		return errcode.Forbidden
+	case ErrUpstreamUnavailable:
+		return errcode.AuthUnavailable
+	}
+	return errcode.Internal
+}
+
+// unavailable converts a transport or decoding failure into an Error
+func unavailable(statusCode int, err error) error {
+	return &Error{
+		Kind:       ErrUpstreamUnavailable,
+		StatusCode: statusCode,
+		Msg:        err.Error(),
+	}
+}
+
+// checkResponse converts the HTTP status and error envelope of a response into an Error.
+// It returns nil when the response grants the request.
+func checkResponse(statusCode int, e *auth.Err) error {
+	var kind error
+
+	switch {
+	case statusCode == http.StatusUnauthorized || e.Code == http.StatusUnauthorized:
+		kind = ErrUnauthorized
+	case statusCode == http.StatusForbidden || e.Code == http.StatusForbidden:
+		kind = ErrForbidden
+	case statusCode >= http.StatusInternalServerError:
+		kind = ErrUpstreamUnavailable
+	case statusCode >= http.StatusBadRequest || e.Status || e.Code != 0:
+		// any other rejection of a rights check is a denial
+		kind = ErrForbidden
+	default:
+		return nil
+	}
+
+	// keep the envelope consistent with the error when the body had none
+	if kind != ErrUpstreamUnavailable && !e.Status && e.Code == 0 {
+		code := statusCode
+		if code < http.StatusBadRequest {
+			code = http.StatusForbidden
+		}
+		*e = auth.Err{Status: true, Msg: http.StatusText(code), Code: code}
+	}
+
+	return &Error{
+		Kind:       kind,
+		StatusCode: statusCode,
+		Code:       e.Code,
+		Msg:        e.Msg,
+	}
+}
+
+// decisionError returns the error of a denied decision, nil when it is allowed
+func decisionError(a auth.Auth) error {
+	if a.Allowed() {
+		return nil
+	}
+	return checkResponse(0, &a.Error)
+}
@@ -0,0 +1,24 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"go-skeleton-auth/pkg/errors"
+)
+
+// Ping checks that the auth API answers. Any answer but a server error will do,
+// the base URL is not an endpoint of its own.
+func (d Data) Ping(ctx context.Context) error {
+	resp, err := d.client.Get(ctx, d.baseURL, "/", nil)
+	if err != nil {
+		return errors.Wrap(unavailable(0, err), "[DATA][Ping]")
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusInternalServerError {
+		return errors.Wrap(unavailable(resp.StatusCode, fmt.Errorf("status %d", resp.StatusCode)), "[DATA][Ping]")
+	}
+	return nil
+}
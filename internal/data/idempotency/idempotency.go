@@ -0,0 +1,127 @@
+package idempotency
+
+import (
+	"context"
+	"log"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/opentracing/opentracing-go"
+
+	jaegerLog "go-skeleton-auth/pkg/log"
+)
+
+type (
+	// Data is the MySQL idempotency store, shared by the replicas
+	Data struct {
+		db   *sqlx.DB
+		stmt map[string]*sqlx.Stmt
+
+		tracer opentracing.Tracer
+		logger jaegerLog.Factory
+	}
+
+	// statement ...
+	statement struct {
+		key   string
+		query string
+	}
+)
+
+// Table definition is in files/sql/idempotency_keys.sql
+const (
+	getRecord  = "GetRecord"
+	qGetRecord = `SELECT idempotency_key, fingerprint, completed, status_code, content_type, body, created_at, expires_at
+		FROM idempotency_keys WHERE idempotency_key = ?`
+
+	insertRecord  = "InsertRecord"
+	qInsertRecord = `INSERT IGNORE INTO idempotency_keys (idempotency_key, fingerprint, created_at, expires_at)
+		VALUES (?, ?, ?, ?)`
+
+	completeRecord  = "CompleteRecord"
+	qCompleteRecord = `UPDATE idempotency_keys SET completed = 1, status_code = ?, content_type = ?, body = ?
+		WHERE idempotency_key = ?`
+
+	deleteRecord  = "DeleteRecord"
+	qDeleteRecord = `DELETE FROM idempotency_keys WHERE idempotency_key = ?`
+
+	deleteExpired  = "DeleteExpired"
+	qDeleteExpired = `DELETE FROM idempotency_keys WHERE idempotency_key = ? AND expires_at <= ?`
+)
+
+var (
+	readStmt = []statement{
+		{getRecord, qGetRecord},
+	}
+	insertStmt = []statement{
+		{insertRecord, qInsertRecord},
+	}
+	updateStmt = []statement{
+		{completeRecord, qCompleteRecord},
+	}
+	deleteStmt = []statement{
+		{deleteRecord, qDeleteRecord},
+		{deleteExpired, qDeleteExpired},
+	}
+)
+
+// New ...
+func New(db *sqlx.DB, tracer opentracing.Tracer, logger jaegerLog.Factory) Data {
+	d := Data{
+		db:     db,
+		tracer: tracer,
+		logger: logger,
+	}
+
+	d.initStmt()
+	return d
+}
+
+func (d *Data) initStmt() {
+	var (
+		err   error
+		stmts = make(map[string]*sqlx.Stmt)
+	)
+
+	for _, v := range readStmt {
+		stmts[v.key], err = d.db.PreparexContext(context.Background(), v.query)
+		if err != nil {
+			log.Fatalf("[DB] Failed to initialize select statement key %v, err : %v", v.key, err)
+		}
+	}
+
+	for _, v := range insertStmt {
+		stmts[v.key], err = d.db.PreparexContext(context.Background(), v.query)
+		if err != nil {
+			log.Fatalf("[DB] Failed to initialize insert statement key %v, err : %v", v.key, err)
+		}
+	}
+
+	for _, v := range updateStmt {
+		stmts[v.key], err = d.db.PreparexContext(context.Background(), v.query)
+		if err != nil {
+			log.Fatalf("[DB] Failed to initialize update statement key %v, err : %v", v.key, err)
+		}
+	}
+
+	for _, v := range deleteStmt {
+		stmts[v.key], err = d.db.PreparexContext(context.Background(), v.query)
+		if err != nil {
+			log.Fatalf("[DB] Failed to initialize delete statement key %v, err : %v", v.key, err)
+		}
+	}
+
+	d.stmt = stmts
+}
+
+// startSpan starts a child span for a query when ctx carries a span
+func (d Data) startSpan(ctx context.Context, operation, query string) (context.Context, func()) {
+	span := opentracing.SpanFromContext(ctx)
+	if span == nil {
+		return ctx, func() {}
+	}
+
+	span = d.tracer.StartSpan(operation, opentracing.ChildOf(span.Context()))
+	span.SetTag("mysql.table", "idempotency_keys")
+	span.SetTag("mysql.query", query)
+	return opentracing.ContextWithSpan(ctx, span), span.Finish
+}
@@ -0,0 +1,64 @@
+package idempotency
+
+import (
+	"context"
+	"time"
+
+	"go-skeleton-auth/internal/entity/idempotency"
+	"go-skeleton-auth/pkg/errors"
+
+	"go.uber.org/zap"
+)
+
+// Reserve inserts rec unless its key is already used by an unexpired record,
+// which is returned with reserved false
+func (d Data) Reserve(ctx context.Context, rec idempotency.Record) (existing idempotency.Record, reserved bool, err error) {
+	ctx, finish := d.startSpan(ctx, "SQL INSERT", qInsertRecord)
+	defer finish()
+
+	if _, err = d.stmt[deleteExpired].ExecContext(ctx, rec.Key, time.Now()); err != nil {
+		d.logger.For(ctx).Error("SQL Query Failed", zap.Error(err))
+		return existing, false, errors.Wrap(err, "[DATA][Reserve]")
+	}
+
+	res, err := d.stmt[insertRecord].ExecContext(ctx, rec.Key, rec.Fingerprint, rec.CreatedAt, rec.ExpiresAt)
+	if err != nil {
+		d.logger.For(ctx).Error("SQL Query Failed", zap.Error(err))
+		return existing, false, errors.Wrap(err, "[DATA][Reserve]")
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return existing, false, errors.Wrap(err, "[DATA][Reserve]")
+	} else if n == 1 {
+		return existing, true, nil
+	}
+
+	if err = d.stmt[getRecord].GetContext(ctx, &existing, rec.Key); err != nil {
+		d.logger.For(ctx).Error("SQL Query Failed", zap.Error(err))
+		return existing, false, errors.Wrap(err, "[DATA][Reserve]")
+	}
+	return existing, false, nil
+}
+
+// Complete stores the response of a reserved record
+func (d Data) Complete(ctx context.Context, rec idempotency.Record) error {
+	ctx, finish := d.startSpan(ctx, "SQL UPDATE", qCompleteRecord)
+	defer finish()
+
+	if _, err := d.stmt[completeRecord].ExecContext(ctx, rec.StatusCode, rec.ContentType, rec.Body, rec.Key); err != nil {
+		d.logger.For(ctx).Error("SQL Query Failed", zap.Error(err))
+		return errors.Wrap(err, "[DATA][Complete]")
+	}
+	return nil
+}
+
+// Release deletes a record so that its key can be retried
+func (d Data) Release(ctx context.Context, key string) error {
+	ctx, finish := d.startSpan(ctx, "SQL DELETE", qDeleteRecord)
+	defer finish()
+
+	if _, err := d.stmt[deleteRecord].ExecContext(ctx, key); err != nil {
+		d.logger.For(ctx).Error("SQL Query Failed", zap.Error(err))
+		return errors.Wrap(err, "[DATA][Release]")
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+package idempotency
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go-skeleton-auth/internal/entity/idempotency"
+)
+
+// Memory is an in-memory idempotency store for single replica deployments and tests,
+// records are lost on restart
+type Memory struct {
+	mu       sync.Mutex
+	records  map[string]idempotency.Record
+	reserves int
+}
+
+// sweepEvery is the number of reservations between two sweeps of the expired records
+const sweepEvery = 1000
+
+// NewMemory ...
+func NewMemory() *Memory {
+	return &Memory{records: make(map[string]idempotency.Record)}
+}
+
+// Reserve inserts rec unless its key is already used by an unexpired record,
+// which is returned with reserved false
+func (m *Memory) Reserve(ctx context.Context, rec idempotency.Record) (idempotency.Record, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+	if m.reserves++; m.reserves%sweepEvery == 0 {
+		for key, r := range m.records {
+			if !r.ExpiresAt.After(now) {
+				delete(m.records, key)
+			}
+		}
+	}
+
+	if existing, ok := m.records[rec.Key]; ok && existing.ExpiresAt.After(now) {
+		return existing, false, nil
+	}
+	m.records[rec.Key] = rec
+	return idempotency.Record{}, true, nil
+}
+
+// Complete stores the response of a reserved record
+func (m *Memory) Complete(ctx context.Context, rec idempotency.Record) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if existing, ok := m.records[rec.Key]; ok {
+		existing.Completed = true
+		existing.StatusCode = rec.StatusCode
+		existing.ContentType = rec.ContentType
+		existing.Body = rec.Body
+		m.records[rec.Key] = existing
+	}
+	return nil
+}
+
+// Release deletes a record so that its key can be retried
+func (m *Memory) Release(ctx context.Context, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.records, key)
+	return nil
+}
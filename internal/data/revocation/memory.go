@@ -0,0 +1,48 @@
+package revocation
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go-skeleton-auth/internal/entity/revocation"
+)
+
+// Memory is an in-memory revocation store for single replica deployments and tests,
+// revocations are lost on restart
+type Memory struct {
+	mu          sync.Mutex
+	revocations []revocation.Revocation
+}
+
+// NewMemory ...
+func NewMemory() *Memory {
+	return &Memory{}
+}
+
+// GetRevocationsAfter returns unexpired revocations with an ID greater than id
+func (m *Memory) GetRevocationsAfter(ctx context.Context, id int64) ([]revocation.Revocation, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var (
+		now    = time.Now()
+		result []revocation.Revocation
+	)
+	for _, rev := range m.revocations {
+		if rev.ID > id && rev.ExpiresAt.After(now) {
+			result = append(result, rev)
+		}
+	}
+	return result, nil
+}
+
+// InsertRevocation inserts rev and returns its ID
+func (m *Memory) InsertRevocation(ctx context.Context, rev revocation.Revocation) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	rev.ID = int64(len(m.revocations) + 1)
+	m.revocations = append(m.revocations, rev)
+	return rev.ID, nil
+}
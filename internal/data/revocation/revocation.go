@@ -0,0 +1,96 @@
+package revocation
+
+import (
+	"context"
+	"log"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/opentracing/opentracing-go"
+
+	jaegerLog "go-skeleton-auth/pkg/log"
+)
+
+type (
+	// Data is the MySQL revocation store
+	Data struct {
+		db   *sqlx.DB
+		stmt map[string]*sqlx.Stmt
+
+		tracer opentracing.Tracer
+		logger jaegerLog.Factory
+	}
+
+	// statement ...
+	statement struct {
+		key   string
+		query string
+	}
+)
+
+// Table definition is in files/sql/token_revocations.sql
+const (
+	getRevocationsAfter  = "GetRevocationsAfter"
+	qGetRevocationsAfter = `SELECT id, jti, subject, issued_before, expires_at, created_at
+		FROM token_revocations WHERE id > ? AND expires_at > ? ORDER BY id`
+
+	insertRevocation  = "InsertRevocation"
+	qInsertRevocation = `INSERT INTO token_revocations (jti, subject, issued_before, expires_at, created_at)
+		VALUES (?, ?, ?, ?, ?)`
+)
+
+var (
+	readStmt = []statement{
+		{getRevocationsAfter, qGetRevocationsAfter},
+	}
+	insertStmt = []statement{
+		{insertRevocation, qInsertRevocation},
+	}
+)
+
+// New ...
+func New(db *sqlx.DB, tracer opentracing.Tracer, logger jaegerLog.Factory) Data {
+	d := Data{
+		db:     db,
+		tracer: tracer,
+		logger: logger,
+	}
+
+	d.initStmt()
+	return d
+}
+
+func (d *Data) initStmt() {
+	var (
+		err   error
+		stmts = make(map[string]*sqlx.Stmt)
+	)
+
+	for _, v := range readStmt {
+		stmts[v.key], err = d.db.PreparexContext(context.Background(), v.query)
+		if err != nil {
+			log.Fatalf("[DB] Failed to initialize select statement key %v, err : %v", v.key, err)
+		}
+	}
+
+	for _, v := range insertStmt {
+		stmts[v.key], err = d.db.PreparexContext(context.Background(), v.query)
+		if err != nil {
+			log.Fatalf("[DB] Failed to initialize insert statement key %v, err : %v", v.key, err)
+		}
+	}
+
+	d.stmt = stmts
+}
+
+// startSpan starts a child span for a query when ctx carries a span
+func (d Data) startSpan(ctx context.Context, operation, query string) (context.Context, func()) {
+	span := opentracing.SpanFromContext(ctx)
+	if span == nil {
+		return ctx, func() {}
+	}
+
+	span = d.tracer.StartSpan(operation, opentracing.ChildOf(span.Context()))
+	span.SetTag("mysql.table", "token_revocations")
+	span.SetTag("mysql.query", query)
+	return opentracing.ContextWithSpan(ctx, span), span.Finish
+}
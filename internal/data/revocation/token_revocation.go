@@ -0,0 +1,49 @@
+package revocation
+
+import (
+	"context"
+	"time"
+
+	"go-skeleton-auth/internal/entity/revocation"
+	"go-skeleton-auth/pkg/errors"
+
+	"go.uber.org/zap"
+)
+
+// GetRevocationsAfter returns unexpired revocations with an ID greater than id
+func (d Data) GetRevocationsAfter(ctx context.Context, id int64) ([]revocation.Revocation, error) {
+	var revocations []revocation.Revocation
+
+	ctx, finish := d.startSpan(ctx, "SQL SELECT", qGetRevocationsAfter)
+	defer finish()
+
+	if err := d.stmt[getRevocationsAfter].SelectContext(ctx, &revocations, id, time.Now()); err != nil {
+		d.logger.For(ctx).Error("SQL Query Failed", zap.Error(err))
+		return nil, errors.Wrap(err, "[DATA][GetRevocationsAfter]")
+	}
+	return revocations, nil
+}
+
+// InsertRevocation inserts rev and returns its ID
+func (d Data) InsertRevocation(ctx context.Context, rev revocation.Revocation) (int64, error) {
+	ctx, finish := d.startSpan(ctx, "SQL INSERT", qInsertRevocation)
+	defer finish()
+
+	res, err := d.stmt[insertRevocation].ExecContext(ctx,
+		rev.JTI,
+		rev.Subject,
+		rev.IssuedBefore,
+		rev.ExpiresAt,
+		rev.CreatedAt,
+	)
+	if err != nil {
+		d.logger.For(ctx).Error("SQL Query Failed", zap.Error(err))
+		return 0, errors.Wrap(err, "[DATA][InsertRevocation]")
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return 0, errors.Wrap(err, "[DATA][InsertRevocation]")
+	}
+	return id, nil
+}
@@ -0,0 +1,80 @@
+package skeleton
+
+import (
+	"context"
+
+	"go-skeleton-auth/internal/entity/skeleton"
+	"go-skeleton-auth/pkg/errors"
+	"go-skeleton-auth/pkg/pagination"
+
+	"github.com/opentracing/opentracing-go"
+)
+
+const (
+	qSelectSkeletons = "SELECT skeleton_id, skeleton_name, created_at FROM skeletons"
+	qCountSkeletons  = "SELECT COUNT(*) FROM skeletons"
+)
+
+// skeletonColumns maps the fields of the list query to columns
+var skeletonColumns = pagination.Columns{
+	"skeleton_id":   "skeleton_id",
+	"skeleton_name": "skeleton_name",
+	"created_at":    "created_at",
+}
+
+// GetSkeletons returns a page of skeletons.
+// The filters and sort are only known per request, so the queries are built
+// with pagination instead of being prepared statements.
+func (d Data) GetSkeletons(ctx context.Context, q pagination.Query) ([]skeleton.Skeleton, pagination.Result, error) {
+	var (
+		skeletons = []skeleton.Skeleton{}
+		result    pagination.Result
+	)
+
+	query, args, err := q.Build(qSelectSkeletons, skeletonColumns)
+	if err != nil {
+		return nil, result, errors.Wrap(err, "[DATA][GetSkeletons]")
+	}
+
+	if span := opentracing.SpanFromContext(ctx); span != nil {
+		span := d.tracer.StartSpan("SQL SELECT", opentracing.ChildOf(span.Context()))
+		span.SetTag("mysql.table", "skeletons")
+		span.SetTag("mysql.query", query)
+		defer span.Finish()
+		ctx = opentracing.ContextWithSpan(ctx, span)
+	}
+
+	if err = d.db.SelectContext(ctx, &skeletons, d.db.Rebind(query), args...); err != nil {
+		return nil, result, errors.Wrap(err, "[DATA][GetSkeletons]")
+	}
+
+	// the count ignores the cursor, it is the total of the whole list
+	count := q
+	count.Cursor = nil
+	where, args, err := count.Where(skeletonColumns)
+	if err != nil {
+		return nil, result, errors.Wrap(err, "[DATA][GetSkeletons]")
+	}
+	if err = d.db.GetContext(ctx, &result.Total, d.db.Rebind(qCountSkeletons+" "+where), args...); err != nil {
+		return nil, result, errors.Wrap(err, "[DATA][GetSkeletons]")
+	}
+
+	n, more := q.Trim(len(skeletons))
+	skeletons = skeletons[:n]
+	if more {
+		last := skeletons[n-1]
+		result.NextCursor = q.NextCursor(func(field string) interface{} {
+			switch field {
+			case "skeleton_id":
+				return last.SkeletonID
+			case "skeleton_name":
+				return last.SkeletonName
+			case "created_at":
+				return last.CreatedAt
+			}
+			return nil
+		})
+	}
+
+	return skeletons, result, nil
+}
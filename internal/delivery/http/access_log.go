@@ -0,0 +1,115 @@
+package http
+
+import (
+	"context"
+	"math/rand"
+	"net/http"
+	"time"
+
+	"go-skeleton-auth/internal/entity"
+	"go-skeleton-auth/pkg/requestid"
+
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+)
+
+// AccessLogOptions ...
+type AccessLogOptions struct {
+	// SampleRate is the fraction of successful requests logged, failed ones
+	// (status >= 400) are always logged. 0 logs every request.
+	SampleRate float64
+	// ExcludePaths are never logged, e.g. health checks
+	ExcludePaths []string
+}
+
+// requestInfo collects what the access log needs from the inner handlers:
+// the route is only known once mux matched it, the user once authenticated
+type requestInfo struct {
+	route   string
+	userID  string
+	traceID string
+}
+
+type requestInfoKey struct{}
+
+func infoFrom(ctx context.Context) *requestInfo {
+	info, _ := ctx.Value(requestInfoKey{}).(*requestInfo)
+	if info == nil {
+		// not behind AccessLogMiddleware, nobody reads it
+		return &requestInfo{}
+	}
+	return info
+}
+
+// withRequestInfo returns the requestInfo of ctx, adding one when there is none
+func withRequestInfo(ctx context.Context) (context.Context, *requestInfo) {
+	if info, ok := ctx.Value(requestInfoKey{}).(*requestInfo); ok {
+		return ctx, info
+	}
+	info := &requestInfo{}
+	return context.WithValue(ctx, requestInfoKey{}, info), info
+}
+
+// withClaims stores the claims of an authenticated request
+func withClaims(r *http.Request, claims entity.ContextValue) *http.Request {
+	if sub, ok := claims.Get("sub").(string); ok {
+		infoFrom(r.Context()).userID = sub
+	}
+	return r.WithContext(context.WithValue(r.Context(), entity.ContextKey("claims"), claims))
+}
+
+// routeMiddleware records the template of the route matched by mux
+func routeMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if route := mux.CurrentRoute(r); route != nil {
+			if tpl, err := route.GetPathTemplate(); err == nil {
+				infoFrom(r.Context()).route = tpl
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// AccessLogMiddleware writes one log line per request
+func (s *Server) AccessLogMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		for _, path := range s.AccessLog.ExcludePaths {
+			if r.URL.Path == path {
+				next.ServeHTTP(w, r)
+				return
+			}
+		}
+
+		start := time.Now()
+		ctx, info := withRequestInfo(r.Context())
+		rw := newResponseWriter(w)
+		next.ServeHTTP(rw, r.WithContext(ctx))
+
+		status := rw.status
+		if status == 0 {
+			status = http.StatusOK
+		}
+		if rate := s.AccessLog.SampleRate; status < http.StatusBadRequest && rate > 0 && rate < 1 && rand.Float64() >= rate {
+			return
+		}
+
+		fields := []zap.Field{
+			zap.String("method", r.Method),
+			zap.String("route", info.route),
+			zap.String("api_version", VersionFrom(r.Context())),
+			zap.String("path", r.URL.Path),
+			zap.Int("status", status),
+			zap.Int("bytes", rw.bytes),
+			zap.Duration("latency", time.Since(start)),
+			zap.String("client_ip", s.clientIP(r)),
+			zap.String("user_id", info.userID),
+			zap.String("trace_id", info.traceID),
+			zap.String("request_id", requestid.FromContext(r.Context())),
+		}
+		if status >= http.StatusInternalServerError {
+			s.Logger.Bg().Error("HTTP access", fields...)
+			return
+		}
+		s.Logger.Bg().Info("HTTP access", fields...)
+	})
+}
@@ -0,0 +1,69 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go-skeleton-auth/internal/entity"
+	jaegerLog "go-skeleton-auth/pkg/log"
+	"go-skeleton-auth/pkg/requestid"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestAccessLogMiddleware(t *testing.T) {
+	core, logs := observer.New(zap.InfoLevel)
+	s := &Server{
+		Logger:    jaegerLog.NewFactory(zap.New(core)),
+		AccessLog: AccessLogOptions{ExcludePaths: []string{"/healthz"}},
+	}
+
+	router := mux.NewRouter()
+	router.Use(routeMiddleware)
+	router.HandleFunc("/items/{id}", func(w http.ResponseWriter, r *http.Request) {
+		withClaims(r, entity.ContextValue{M: map[string]interface{}{"sub": "user-1"}})
+		w.Write([]byte("hello"))
+	})
+	router.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {})
+	router.HandleFunc("/fail", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	})
+	h := requestid.Middleware(s.AccessLogMiddleware(router))
+
+	r := httptest.NewRequest(http.MethodGet, "/items/42", nil)
+	r.RemoteAddr = "10.0.0.1:5555"
+	h.ServeHTTP(httptest.NewRecorder(), r)
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/healthz", nil))
+
+	require.Equal(t, 1, logs.Len())
+	fields := logs.All()[0].ContextMap()
+	require.Equal(t, "GET", fields["method"])
+	require.Equal(t, "/items/{id}", fields["route"])
+	require.Equal(t, "/items/42", fields["path"])
+	require.Equal(t, int64(http.StatusOK), fields["status"])
+	require.Equal(t, int64(5), fields["bytes"])
+	require.Equal(t, "10.0.0.1", fields["client_ip"])
+	require.Equal(t, "user-1", fields["user_id"])
+	require.NotEmpty(t, fields["request_id"])
+	require.Contains(t, fields, "latency")
+
+	// sampled out successes, failures are always logged
+	s.AccessLog.SampleRate = 0.000001
+	logs.TakeAll()
+	for i := 0; i < 10; i++ {
+		h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/items/1", nil))
+	}
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/fail", nil))
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/missing", nil))
+
+	entries := logs.TakeAll()
+	require.Len(t, entries, 2)
+	require.Equal(t, int64(http.StatusBadGateway), entries[0].ContextMap()["status"])
+	require.Equal(t, zap.ErrorLevel, entries[0].Level)
+	require.Equal(t, int64(http.StatusNotFound), entries[1].ContextMap()["status"])
+	require.Equal(t, "", entries[1].ContextMap()["route"])
+}
@@ -0,0 +1,34 @@
+package apikey
+
+import (
+	"context"
+
+	"go-skeleton-auth/internal/entity/apikey"
+	jaegerLog "go-skeleton-auth/pkg/log"
+
+	"github.com/opentracing/opentracing-go"
+)
+
+// IAPIKeySvc is an interface to API Key Service
+type IAPIKeySvc interface {
+	CreateAPIKey(ctx context.Context, owner string, scopes []string) (string, apikey.APIKey, error)
+	RotateAPIKey(ctx context.Context, id int64) (apikey.Rotated, error)
+}
+
+type (
+	// Handler ...
+	Handler struct {
+		apikeySvc IAPIKeySvc
+		tracer    opentracing.Tracer
+		logger    jaegerLog.Factory
+	}
+)
+
+// New for bridging API key handler initialization
+func New(is IAPIKeySvc, tracer opentracing.Tracer, logger jaegerLog.Factory) *Handler {
+	return &Handler{
+		apikeySvc: is,
+		tracer:    tracer,
+		logger:    logger,
+	}
+}
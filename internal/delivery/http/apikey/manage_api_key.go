@@ -0,0 +1,58 @@
+package apikey
+
+import (
+	"context"
+	httpHelper "go-skeleton-auth/internal/delivery/http"
+	"go-skeleton-auth/internal/entity/apikey"
+	"net/http"
+)
+
+// CreateAPIKey godoc
+// @Summary Create an API key
+// @Description Create an API key for an owner, the key is only returned once
+// @Tags Admin
+// @Accept  json
+// @Produce  json
+// @Security BearerAuth
+// @Param body body apikey.CreateRequest true "API key"
+// @Success 200 {object} apikey.Created
+// @Failure 400 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 422 {object} response.Response
+// @Router /admin/apikeys [post]
+func (h *Handler) CreateAPIKey(w http.ResponseWriter, r *http.Request) {
+	// the response carries the plaintext key, it must not be kept, e.g. for idempotent replays
+	w.Header().Set("Cache-Control", "no-store")
+	httpHelper.Handle("CreateAPIKey", h.tracer, h.logger, h.createAPIKey)(w, r)
+}
+
+func (h *Handler) createAPIKey(ctx context.Context, req apikey.CreateRequest) (apikey.Created, struct{}, error) {
+	key, k, err := h.apikeySvc.CreateAPIKey(ctx, req.Owner, req.Scopes)
+	if err != nil {
+		return apikey.Created{}, struct{}{}, err
+	}
+	return apikey.Created{Key: key, APIKey: k}, struct{}{}, nil
+}
+
+// RotateAPIKey godoc
+// @Summary Rotate an API key
+// @Description Replace an API key by a new one, the old key keeps working for the rotation overlap
+// @Tags Admin
+// @Produce  json
+// @Security BearerAuth
+// @Param id path int true "API key ID"
+// @Success 200 {object} apikey.Rotated
+// @Failure 400 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Router /admin/apikeys/{id}/rotate [post]
+func (h *Handler) RotateAPIKey(w http.ResponseWriter, r *http.Request) {
+	// the response carries the plaintext key, it must not be kept, e.g. for idempotent replays
+	w.Header().Set("Cache-Control", "no-store")
+	httpHelper.Handle("RotateAPIKey", h.tracer, h.logger, h.rotateAPIKey)(w, r)
+}
+
+func (h *Handler) rotateAPIKey(ctx context.Context, req apikey.RotateRequest) (apikey.Rotated, struct{}, error) {
+	result, err := h.apikeySvc.RotateAPIKey(ctx, req.ID)
+	return result, struct{}{}, err
+}
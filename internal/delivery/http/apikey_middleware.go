@@ -0,0 +1,101 @@
+package http
+
+import (
+	"context"
+	"crypto/x509"
+	"go-skeleton-auth/internal/entity"
+	"go-skeleton-auth/internal/entity/apikey"
+	"go-skeleton-auth/internal/entity/errcode"
+	"go-skeleton-auth/pkg/errors"
+	"go-skeleton-auth/pkg/tlsutil"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"go.uber.org/zap"
+)
+
+// APIKeyAuthenticator ...
+type APIKeyAuthenticator interface {
+	Authenticate(ctx context.Context, key string) (apikey.APIKey, error)
+}
+
+// AuthMiddleware authenticates requests with an API key when one is present,
+// with a Bearer JWT when an Authorization header is present and with the
+// verified TLS client certificate otherwise
+func (s *Server) AuthMiddleware(next http.Handler) http.Handler {
+	jwtNext := s.JWTMiddleware(next)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := apiKeyFromRequest(r)
+		if key == "" || s.APIKey == nil {
+			if cert := tlsutil.PeerIdentity(r.TLS); cert != nil && r.Header.Get("Authorization") == "" {
+				r = withClaims(r, certClaims(cert))
+				next.ServeHTTP(w, r)
+				return
+			}
+			jwtNext.ServeHTTP(w, r)
+			return
+		}
+
+		k, err := s.APIKey.Authenticate(r.Context(), key)
+		if err != nil {
+			// only unknown and expired keys are authentication failures
+			if code, _ := errors.CodeOf(err); code == errcode.InvalidAPIKey {
+				errInvalidAPIKey.render(w, r)
+				return
+			}
+			s.Logger.For(r.Context()).Error("API key authentication failed", zap.Error(err))
+			resp := ParseErrorCode(err)
+			resp.Render(w, r)
+			return
+		}
+
+		// scopes are exposed as permissions, the same claim the JWT carries
+		scopes := make([]interface{}, 0, len(k.ScopeList()))
+		for _, scope := range k.ScopeList() {
+			scopes = append(scopes, scope)
+		}
+		ctxVal := entity.ContextValue{
+			M: map[string]interface{}{
+				"sub":   "apikey:" + strconv.FormatInt(k.ID, 10),
+				"owner": k.Owner,
+				"permissions": map[string]interface{}{
+					"apikey": scopes,
+				},
+			},
+		}
+		r = withClaims(r, ctxVal)
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// certClaims exposes a client certificate identity the way JWT claims are exposed
+func certClaims(cert *x509.Certificate) entity.ContextValue {
+	return entity.ContextValue{
+		M: map[string]interface{}{
+			"sub":      "cert:" + cert.Subject.CommonName,
+			"cn":       cert.Subject.CommonName,
+			"org":      strings.Join(cert.Subject.Organization, ","),
+			"ou":       strings.Join(cert.Subject.OrganizationalUnit, ","),
+			"dns":      strings.Join(cert.DNSNames, ","),
+			"serial":   cert.SerialNumber.String(),
+			"issuer":   cert.Issuer.CommonName,
+			"auth_via": "mtls",
+		},
+	}
+}
+
+// apiKeyFromRequest reads the key from X-API-Key or an "Authorization: ApiKey <key>" header
+func apiKeyFromRequest(r *http.Request) string {
+	if key := r.Header.Get("X-API-Key"); key != "" {
+		return key
+	}
+
+	authorization := r.Header.Get("Authorization")
+	if strings.HasPrefix(authorization, "ApiKey ") {
+		return strings.TrimSpace(strings.TrimPrefix(authorization, "ApiKey "))
+	}
+	return ""
+}
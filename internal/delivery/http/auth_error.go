@@ -0,0 +1,69 @@
+package http
+
+import (
+	"fmt"
+	"go-skeleton-auth/internal/entity/errcode"
+	"go-skeleton-auth/pkg/errors"
+	"go-skeleton-auth/pkg/response"
+	"net/http"
+)
+
+// authRealm is the realm advertised in WWW-Authenticate challenges
+const authRealm = "example"
+
+// authError is an authentication or authorization failure.
+// Authentication failures (401) carry a WWW-Authenticate challenge,
+// authorization failures (403) do not.
+type authError struct {
+	code errors.Code
+	// msg overrides the default message of the code
+	msg string
+	// oauthError is the RFC 6750 error attribute of the challenge, e.g. invalid_token
+	oauthError string
+}
+
+var (
+	errMissingCredentials = authError{errcode.MissingCredentials, "", ""}
+	errUnsupportedScheme  = authError{errcode.UnsupportedScheme, "", "invalid_request"}
+	errMalformedToken     = authError{errcode.InvalidToken, "Invalid token: malformed authorization header", "invalid_request"}
+	errInvalidToken       = authError{errcode.InvalidToken, "", "invalid_token"}
+	errExpiredToken       = authError{errcode.ExpiredToken, "", "invalid_token"}
+	errRevokedToken       = authError{errcode.RevokedToken, "", "invalid_token"}
+	errInvalidAPIKey      = authError{errcode.InvalidAPIKey, "", "invalid_token"}
+	errRevocationDown     = authError{errcode.RevocationCheckDown, "", ""}
+)
+
+// render writes the failure in the standard response envelope
+func (e authError) render(w http.ResponseWriter, r *http.Request) {
+	info, _ := errors.Lookup(e.code)
+	if e.msg == "" {
+		e.msg = info.Message
+	}
+
+	if info.HTTPStatus == http.StatusUnauthorized {
+		for _, challenge := range e.challenges() {
+			w.Header().Add("WWW-Authenticate", challenge)
+		}
+	}
+
+	resp := response.Response{
+		StatusCode: info.HTTPStatus,
+		Error: response.Error{
+			Status: true,
+			Msg:    e.msg,
+			Code:   int(e.code),
+		},
+	}
+	resp.Render(w, r)
+}
+
+func (e authError) challenges() []string {
+	bearer := fmt.Sprintf("Bearer realm=%q", authRealm)
+	if e.oauthError != "" {
+		bearer += fmt.Sprintf(", error=%q, error_description=%q", e.oauthError, e.msg)
+	}
+	return []string{
+		bearer,
+		fmt.Sprintf("ApiKey realm=%q", authRealm),
+	}
+}
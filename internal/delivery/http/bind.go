@@ -0,0 +1,24 @@
+package http
+
+import (
+	"go-skeleton-auth/internal/entity/errcode"
+	"go-skeleton-auth/pkg/binding"
+	"go-skeleton-auth/pkg/errors"
+	"net/http"
+)
+
+// Bind decodes and validates r into dst, see pkg/binding.
+// Malformed requests carry errcode.BadRequest and invalid ones errcode.ValidationFailed,
+// ParseErrorCode renders them with every field error.
+func Bind(r *http.Request, dst interface{}) error {
+	err := binding.Bind(r, dst)
+	switch errors.Cause(err) {
+	case nil:
+		return nil
+	case binding.ErrMalformed:
+		return errors.WithCode(err, errcode.BadRequest)
+	case binding.ErrInvalid:
+		return errors.WithCode(err, errcode.ValidationFailed)
+	}
+	return err
+}
@@ -0,0 +1,31 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go-skeleton-auth/internal/entity/errcode"
+	"go-skeleton-auth/pkg/response"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBindErrors(t *testing.T) {
+	var req struct {
+		ID   int    `path:"id"`
+		Name string `json:"name" validate:"required"`
+	}
+
+	r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"name":""}`))
+	resp := ParseErrorCode(Bind(r, &req))
+	require.Equal(t, http.StatusUnprocessableEntity, resp.StatusCode)
+	require.Equal(t, int(errcode.ValidationFailed), resp.Error.Code)
+	require.Equal(t, []response.FieldError{{Field: "name", Rule: "required", Msg: "is required"}}, resp.Error.Fields)
+
+	r = httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"name":`))
+	resp = ParseErrorCode(Bind(r, &req))
+	require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	require.Len(t, resp.Error.Fields, 1)
+}
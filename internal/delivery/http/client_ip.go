@@ -0,0 +1,50 @@
+package http
+
+import (
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+)
+
+// clientIP is the address of the client. X-Forwarded-For is only read when the
+// peer is a trusted proxy, the client is then the last address of the header
+// that is not a trusted proxy itself. The peer address is used otherwise.
+func (s *Server) clientIP(r *http.Request) string {
+	peer := r.RemoteAddr
+	if host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+		peer = host
+	}
+	if !s.trustedProxy(peer) {
+		return peer
+	}
+
+	xff := strings.Split(strings.Join(r.Header.Values("X-Forwarded-For"), ","), ",")
+	client := peer
+	for i := len(xff) - 1; i >= 0; i-- {
+		addr := strings.TrimSpace(xff[i])
+		if addr == "" {
+			continue
+		}
+		client = addr
+		if !s.trustedProxy(addr) {
+			break
+		}
+	}
+	return client
+}
+
+// trustedProxy reports whether addr is in one of the TrustedProxies
+func (s *Server) trustedProxy(addr string) bool {
+	ip, err := netip.ParseAddr(addr)
+	if err != nil {
+		return false
+	}
+	ip = ip.Unmap()
+	for _, p := range s.TrustedProxies {
+		if p.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,43 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientIP(t *testing.T) {
+	s := &Server{TrustedProxies: []netip.Prefix{
+		netip.MustParsePrefix("10.0.0.0/8"),
+		netip.MustParsePrefix("192.168.1.1/32"),
+	}}
+
+	testCases := []struct {
+		name   string
+		remote string
+		xff    []string
+		want   string
+	}{
+		{"no proxy", "203.0.113.7:5555", nil, "203.0.113.7"},
+		{"untrusted peer spoofing", "203.0.113.7:5555", []string{"1.2.3.4"}, "203.0.113.7"},
+		{"trusted peer", "10.1.2.3:5555", []string{"198.51.100.9"}, "198.51.100.9"},
+		{"client prepending a fake address", "10.1.2.3:5555", []string{"1.2.3.4, 198.51.100.9"}, "198.51.100.9"},
+		{"chain of trusted proxies", "10.1.2.3:5555", []string{"198.51.100.9, 192.168.1.1", "10.9.9.9"}, "198.51.100.9"},
+		{"trusted peer without header", "10.1.2.3:5555", nil, "10.1.2.3"},
+		{"only trusted addresses", "10.1.2.3:5555", []string{"10.4.4.4"}, "10.4.4.4"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			r.RemoteAddr = tc.remote
+			for _, v := range tc.xff {
+				r.Header.Add("X-Forwarded-For", v)
+			}
+			require.Equal(t, tc.want, s.clientIP(r))
+		})
+	}
+}
@@ -0,0 +1,110 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+
+	"go-skeleton-auth/pkg/errors"
+	jaegerLog "go-skeleton-auth/pkg/log"
+	"go-skeleton-auth/pkg/response"
+
+	"github.com/opentracing/opentracing-go"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// Endpoint is a typed handler. Req is bound from the request,
+// Resp is rendered as data and Meta as metadata.
+type Endpoint[Req, Resp, Meta any] func(ctx context.Context, req Req) (Resp, Meta, error)
+
+// Binder is implemented by requests that bind themselves instead of using Bind,
+// e.g. to parse pagination parameters
+type Binder interface {
+	Bind(r *http.Request) error
+}
+
+// Handle turns an Endpoint into an http.HandlerFunc. It binds the request,
+// starts a span named name under the server span of TracingMiddleware, logs errors, maps them with
+// ParseErrorCode and renders the response, like every handler used to do by hand:
+//
+//	func (h *Handler) GetSkeleton(w http.ResponseWriter, r *http.Request) {
+//		httpHelper.Handle("GetSkeleton", h.tracer, h.logger, h.getSkeleton)(w, r)
+//	}
+//
+// The access log line, the one line of every request, is written by AccessLogMiddleware.
+func Handle[Req, Resp, Meta any](name string, tracer opentracing.Tracer, logger jaegerLog.Factory, endpoint Endpoint[Req, Resp, Meta]) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var (
+			req  Req
+			resp response.Response
+		)
+
+		span, ctx := opentracing.StartSpanFromContextWithTracer(r.Context(), tracer, name)
+		defer span.Finish()
+
+		err := bind(r, &req)
+		if err == nil {
+			var (
+				result Resp
+				meta   Meta
+			)
+			result, meta, err = endpoint(ctx, req)
+			resp.Data = nonZero(result)
+			resp.Metadata = nonZero(meta)
+		}
+
+		if err != nil {
+			resp = ParseErrorCode(err)
+			_, info := errorInfo(err)
+			logAt(logger.For(ctx), info.Level, "HTTP request error", zap.String("method", r.Method), zap.Stringer("url", r.URL), zap.Error(err))
+		}
+
+		// not deferred, a panicking endpoint is answered by RecoverMiddleware
+		resp.Render(w, r)
+	}
+}
+
+// logAt logs msg at level, the log level of an error code
+func logAt(logger jaegerLog.Logger, level errors.Level, msg string, fields ...zapcore.Field) {
+	switch level {
+	case errors.LevelDebug:
+		logger.Debug(msg, fields...)
+	case errors.LevelInfo:
+		logger.Info(msg, fields...)
+	case errors.LevelWarn:
+		logger.Warn(msg, fields...)
+	default:
+		logger.Error(msg, fields...)
+	}
+}
+
+// bind binds r into req with its Bind method, or with Bind when req is a struct
+func bind(r *http.Request, req interface{}) error {
+	if b, ok := req.(Binder); ok {
+		return b.Bind(r)
+	}
+	if reflect.TypeOf(req).Elem().Kind() == reflect.Struct {
+		return Bind(r, req)
+	}
+	return nil
+}
+
+// nonZero returns v, or nil when it is empty so that it is omitted:
+// a nil pointer, slice or map, or a value without fields like struct{}
+func nonZero(v interface{}) interface{} {
+	if v == nil {
+		return nil
+	}
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Ptr, reflect.Slice, reflect.Map, reflect.Interface:
+		if rv.IsNil() {
+			return nil
+		}
+	}
+	if rv.Type().Size() == 0 {
+		return nil
+	}
+	return v
+}
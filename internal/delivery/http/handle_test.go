@@ -0,0 +1,144 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go-skeleton-auth/internal/entity/errcode"
+	"go-skeleton-auth/pkg/errors"
+	jaegerLog "go-skeleton-auth/pkg/log"
+
+	"github.com/gorilla/mux"
+	"github.com/opentracing/opentracing-go/mocktracer"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+type greetRequest struct {
+	ID   int    `path:"id"`
+	Name string `json:"name" validate:"required"`
+}
+
+type greeting struct {
+	Message string `json:"message"`
+}
+
+type pageRequest struct {
+	Page string
+}
+
+func (p *pageRequest) Bind(r *http.Request) error {
+	p.Page = r.URL.Query().Get("page")
+	if p.Page == "" {
+		return errors.NewCode(errcode.BadRequest, "page is required")
+	}
+	return nil
+}
+
+func TestHandle(t *testing.T) {
+	tracer := mocktracer.New()
+	logger := jaegerLog.NewFactory(zap.NewNop())
+
+	greet := Handle("Greet", tracer, logger, func(ctx context.Context, req greetRequest) (greeting, struct{}, error) {
+		if req.Name == "forbidden" {
+			return greeting{}, struct{}{}, errors.NewCode(errcode.Forbidden, "no greeting")
+		}
+		return greeting{Message: "hello " + req.Name}, struct{}{}, nil
+	})
+	paged := Handle("Paged", tracer, logger, func(ctx context.Context, req pageRequest) ([]string, map[string]string, error) {
+		return []string{"a"}, map[string]string{"page": req.Page}, nil
+	})
+
+	testCases := []struct {
+		name    string
+		handler http.HandlerFunc
+		body    string
+		target  string
+		status  int
+		code    int
+		data    interface{}
+		meta    interface{}
+	}{
+		{"success", greet, `{"name":"bone"}`, "/", http.StatusOK, 0, map[string]interface{}{"message": "hello bone"}, nil},
+		{"validation", greet, `{}`, "/", http.StatusUnprocessableEntity, int(errcode.ValidationFailed), nil, nil},
+		{"endpoint error", greet, `{"name":"forbidden"}`, "/", http.StatusForbidden, int(errcode.Forbidden), nil, nil},
+		{"custom binder", paged, "", "/?page=2", http.StatusOK, 0, []interface{}{"a"}, map[string]interface{}{"page": "2"}},
+		{"custom binder error", paged, "", "/", http.StatusBadRequest, int(errcode.BadRequest), nil, nil},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			r := mux.SetURLVars(httptest.NewRequest(http.MethodPost, tc.target, strings.NewReader(tc.body)), map[string]string{"id": "1"})
+			w := httptest.NewRecorder()
+			tc.handler(w, r)
+
+			require.Equal(t, tc.status, w.Code)
+
+			var body struct {
+				Data     interface{} `json:"data"`
+				Metadata interface{} `json:"metadata"`
+				Error    struct {
+					Code int `json:"code"`
+				} `json:"error"`
+			}
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+			require.Equal(t, tc.code, body.Error.Code)
+			require.Equal(t, tc.data, body.Data)
+			require.Equal(t, tc.meta, body.Metadata)
+		})
+	}
+
+	spans := tracer.FinishedSpans()
+	require.Len(t, spans, len(testCases))
+	require.Equal(t, "Greet", spans[0].OperationName)
+}
+
+func TestHandleLogLevel(t *testing.T) {
+	core, logs := observer.New(zap.DebugLevel)
+	logger := jaegerLog.NewFactory(zap.New(core))
+
+	failing := Handle("Failing", mocktracer.New(), logger, func(ctx context.Context, req struct{}) (struct{}, struct{}, error) {
+		if ctx.Value(failKey{}) != nil {
+			return struct{}{}, struct{}{}, errors.New("db down")
+		}
+		return struct{}{}, struct{}{}, errors.NewCode(errcode.Forbidden, "no greeting")
+	})
+
+	failing(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	failing(httptest.NewRecorder(), r.WithContext(context.WithValue(r.Context(), failKey{}, true)))
+
+	entries := logs.FilterMessage("HTTP request error").All()
+	require.Len(t, entries, 2)
+	// errors are logged at the level of their code
+	require.Equal(t, zap.InfoLevel, entries[0].Level)
+	require.Equal(t, zap.ErrorLevel, entries[1].Level)
+}
+
+type failKey struct{}
+
+func TestHandlePanic(t *testing.T) {
+	logger := jaegerLog.NewFactory(zap.NewNop())
+	s := &Server{Logger: logger}
+
+	h := s.RecoverMiddleware(Handle("Panicking", mocktracer.New(), logger, func(ctx context.Context, req struct{}) (greeting, struct{}, error) {
+		panic("boom")
+	}))
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	// the endpoint never returned, Handle must not render a 200 on its way out
+	require.Equal(t, http.StatusInternalServerError, w.Code)
+	var body struct {
+		Error struct {
+			Code int `json:"code"`
+		} `json:"error"`
+	}
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&body))
+	require.Equal(t, int(errcode.Panic), body.Error.Code)
+}
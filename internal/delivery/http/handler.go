@@ -2,7 +2,7 @@ package http
 
 import (
 	"errors"
-	"log"
+	"expvar"
 	"net/http"
 
 	"go-skeleton-auth/pkg/response"
@@ -15,20 +15,37 @@ import (
 // Handler will initialize mux router and register handler
 func (s *Server) Handler() *mux.Router {
 	r := mux.NewRouter()
+	r.Use(routeMiddleware, s.TimeoutMiddleware)
 	// Jika tidak ditemukan, jangan diubah.
 	r.NotFoundHandler = http.HandlerFunc(notFoundHandler)
 	// Health Check
 	r.HandleFunc("", defaultHandler).Methods("GET")
 	r.HandleFunc("/", defaultHandler).Methods("GET")
+	r.HandleFunc("/healthz", s.healthz).Methods("GET")
+	r.HandleFunc("/readyz", s.readyz).Methods("GET")
+	// Metrics, e.g. http_panics_total, only for authenticated callers
+	debug := r.PathPrefix("/debug").Subrouter()
+	debug.Use(s.IPRateLimitMiddleware, s.AuthMiddleware, s.RateLimitMiddleware)
+	debug.Handle("/vars", expvar.Handler()).Methods("GET")
 
-	// Tambahan Prefix di depan API endpoint
-	router := r.PathPrefix("/example").Subrouter()
+	// Tambahan Prefix di depan API endpoint, the version segment
+	// is already removed by VersionMiddleware
+	router := r.PathPrefix(s.basePath()).Subrouter()
 
 	// Routes
 	skeleton := router.PathPrefix("/skeleton").Subrouter()
-	skeleton.Use(s.JWTMiddleware)
+	skeleton.Use(s.IPRateLimitMiddleware, s.AuthMiddleware, s.RateLimitMiddleware)
 	skeleton.HandleFunc("", s.Skeleton.GetSkeleton).Methods("GET")
 
+	// Admin
+	admin := router.PathPrefix("/admin").Subrouter()
+	admin.Use(s.IPRateLimitMiddleware, s.AuthMiddleware, s.RateLimitMiddleware, s.IdempotencyMiddleware)
+	admin.HandleFunc("/tokens/revoke", s.RevocationHandler.RevokeToken).Methods("POST")
+	admin.HandleFunc("/apikeys", s.APIKeyHandler.CreateAPIKey).Methods("POST")
+	admin.HandleFunc("/apikeys/{id:[0-9]+}/rotate", s.APIKeyHandler.RotateAPIKey).Methods("POST")
+
+	// Swagger UI and document of the version of the request
+	router.HandleFunc("/swagger/doc.json", swaggerDoc).Methods("GET")
 	router.PathPrefix("/swagger").Handler(httpSwagger.WrapHandler)
 	return r
 }
@@ -44,7 +61,7 @@ func notFoundHandler(w http.ResponseWriter, r *http.Request) {
 		errRes response.Error
 	)
 	resp = &response.Response{}
-	defer resp.RenderJSON(w, r)
+	defer resp.Render(w, r)
 
 	err = errors.New("404 Not Found")
 
@@ -56,7 +73,6 @@ func notFoundHandler(w http.ResponseWriter, r *http.Request) {
 			Status: true,
 		}
 
-		log.Printf("[ERROR] %s %s - %v\n", r.Method, r.URL, err)
 		resp.StatusCode = 404
 		resp.Error = errRes
 		return
@@ -0,0 +1,32 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type fakeHandlers struct{}
+
+func (fakeHandlers) GetSkeleton(w http.ResponseWriter, r *http.Request)  {}
+func (fakeHandlers) RevokeToken(w http.ResponseWriter, r *http.Request)  {}
+func (fakeHandlers) CreateAPIKey(w http.ResponseWriter, r *http.Request) {}
+func (fakeHandlers) RotateAPIKey(w http.ResponseWriter, r *http.Request) {}
+
+func TestDebugVarsRequireAuth(t *testing.T) {
+	s := &Server{Skeleton: fakeHandlers{}, RevocationHandler: fakeHandlers{}, APIKeyHandler: fakeHandlers{}, APIKey: fakeAPIKey{}}
+	h := s.Handler()
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/debug/vars", nil))
+	require.Equal(t, http.StatusUnauthorized, rec.Code)
+
+	req := httptest.NewRequest(http.MethodGet, "/debug/vars", nil)
+	req.Header.Set("X-API-Key", "valid-key")
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Contains(t, rec.Body.String(), "memstats")
+}
@@ -0,0 +1,41 @@
+package http
+
+import (
+	"net/http"
+
+	"go-skeleton-auth/internal/entity/errcode"
+	"go-skeleton-auth/pkg/errors"
+	"go-skeleton-auth/pkg/health"
+	"go-skeleton-auth/pkg/response"
+
+	"go.uber.org/zap"
+)
+
+// healthz is the liveness probe, the process answers.
+// It does not check the dependencies, a failing one is no reason to restart.
+func (s *Server) healthz(w http.ResponseWriter, r *http.Request) {
+	resp := response.Response{Data: health.Report{Status: health.StatusOK}}
+	resp.Render(w, r)
+}
+
+// readyz is the readiness probe, every dependency registered in s.Health is
+// healthy and the server is not draining before shutdown.
+// Only the status and latency of the checks are sent, their errors are logged.
+func (s *Server) readyz(w http.ResponseWriter, r *http.Request) {
+	report := health.Report{Status: health.StatusOK}
+	if s.Health != nil {
+		report = s.Health.Check(r.Context())
+	}
+	for name, res := range report.Checks {
+		if res.Err != nil {
+			s.Logger.For(r.Context()).Warn("Readiness check failed", zap.String("check", name), zap.Error(res.Err))
+		}
+	}
+
+	resp := response.Response{Data: report}
+	if !report.OK() {
+		resp = ParseErrorCode(errors.NewCode(errcode.NotReady, report.Status))
+		resp.Data = report
+	}
+	resp.Render(w, r)
+}
@@ -0,0 +1,67 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go-skeleton-auth/internal/entity/errcode"
+	"go-skeleton-auth/pkg/errors"
+	"go-skeleton-auth/pkg/health"
+	jaegerLog "go-skeleton-auth/pkg/log"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestHealthEndpoints(t *testing.T) {
+	var dbErr error
+	core, logs := observer.New(zap.InfoLevel)
+	s := &Server{Health: health.New(0), Logger: jaegerLog.NewFactory(zap.New(core))}
+	s.Health.Register("mysql", func(ctx context.Context) error { return dbErr })
+
+	var raw string
+	get := func(h http.HandlerFunc) (int, health.Report, int) {
+		w := httptest.NewRecorder()
+		h(w, httptest.NewRequest(http.MethodGet, "/", nil))
+		raw = w.Body.String()
+
+		var body struct {
+			Data  health.Report `json:"data"`
+			Error struct {
+				Code int `json:"code"`
+			} `json:"error"`
+		}
+		require.NoError(t, json.NewDecoder(w.Body).Decode(&body))
+		return w.Code, body.Data, body.Error.Code
+	}
+
+	status, report, _ := get(s.readyz)
+	require.Equal(t, http.StatusOK, status)
+	require.Equal(t, health.StatusOK, report.Checks["mysql"].Status)
+
+	dbErr = errors.New("connection refused")
+	status, report, code := get(s.readyz)
+	require.Equal(t, http.StatusServiceUnavailable, status)
+	require.Equal(t, int(errcode.NotReady), code)
+	require.Equal(t, health.StatusFail, report.Status)
+	require.Equal(t, health.StatusFail, report.Checks["mysql"].Status)
+	// the cause is logged, never sent to the unauthenticated caller
+	require.NotContains(t, raw, "connection refused")
+	entries := logs.FilterMessage("Readiness check failed").All()
+	require.Len(t, entries, 1)
+	require.Equal(t, "connection refused", entries[0].ContextMap()["error"])
+
+	// liveness does not depend on the dependencies nor on draining
+	s.Health.Drain()
+	status, report, _ = get(s.readyz)
+	require.Equal(t, http.StatusServiceUnavailable, status)
+	require.Equal(t, health.StatusDraining, report.Status)
+
+	status, report, _ = get(s.healthz)
+	require.Equal(t, http.StatusOK, status)
+	require.Equal(t, health.StatusOK, report.Status)
+}
@@ -0,0 +1,181 @@
+package http
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"go-skeleton-auth/internal/entity/errcode"
+	"go-skeleton-auth/internal/entity/idempotency"
+	"go-skeleton-auth/pkg/errors"
+
+	"go.uber.org/zap"
+)
+
+const (
+	idempotencyHeader = "Idempotency-Key"
+	// replayedHeader marks the responses replayed from the store
+	replayedHeader = "Idempotent-Replayed"
+
+	maxIdempotencyKey         = 255
+	defaultIdempotencyTTL     = 24 * time.Hour
+	defaultIdempotencyMaxBody = 10 << 20
+)
+
+// IdempotencyMiddleware replays the response of a POST, PUT or PATCH retried with the
+// same Idempotency-Key, per principal. Reusing a key for a different request is
+// rejected, and so is a retry while the first request is in flight. The body is kept
+// to fingerprint the request, bodies larger than IdempotencyMaxBody are rejected.
+// Server errors are not stored so that they can be retried, nor are responses marked
+// Cache-Control: no-store, e.g. carrying a secret, and requests go through without
+// protection when the store fails.
+func (s *Server) IdempotencyMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := r.Header.Get(idempotencyHeader)
+		if s.Idempotency == nil || key == "" || !unsafeMethod(r.Method) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if !validIdempotencyKey(key) {
+			resp := ParseErrorCode(errors.NewCode(errcode.InvalidIdempotencyKey, "at most 255 printable ASCII characters"))
+			resp.Render(w, r)
+			return
+		}
+
+		limit := s.IdempotencyMaxBody
+		if limit <= 0 {
+			limit = defaultIdempotencyMaxBody
+		}
+		body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, limit))
+		if err != nil {
+			code := errcode.BadRequest
+			// the reader fails once the limit is reached
+			if int64(len(body)) >= limit {
+				code = errcode.PayloadTooLarge
+			}
+			resp := ParseErrorCode(errors.WithCode(err, code))
+			resp.Render(w, r)
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+
+		ttl := s.IdempotencyTTL
+		if ttl <= 0 {
+			ttl = defaultIdempotencyTTL
+		}
+		now := time.Now()
+		rec := idempotency.Record{
+			Key:         hash(s.principal(r), key),
+			Fingerprint: hash(r.Method, r.URL.RequestURI(), string(body)),
+			CreatedAt:   now,
+			ExpiresAt:   now.Add(ttl),
+		}
+
+		existing, reserved, err := s.Idempotency.Reserve(r.Context(), rec)
+		if err != nil {
+			s.Logger.For(r.Context()).Error("Idempotency store failed", zap.Error(err))
+			next.ServeHTTP(w, r)
+			return
+		}
+		if !reserved {
+			replay(w, r, existing, rec.Fingerprint)
+			return
+		}
+
+		// release the key when the handler panics
+		stored := false
+		defer func() {
+			if !stored {
+				s.releaseIdempotencyKey(r, rec.Key)
+			}
+		}()
+
+		rw := newResponseWriter(w)
+		rw.body = &bytes.Buffer{}
+		next.ServeHTTP(rw, r)
+
+		rec.StatusCode = rw.status
+		if rec.StatusCode == 0 {
+			rec.StatusCode = http.StatusOK
+		}
+		if rec.StatusCode >= http.StatusInternalServerError || noStore(rw.Header()) {
+			return
+		}
+		rec.ContentType = rw.Header().Get("Content-Type")
+		rec.Body = rw.body.Bytes()
+		if err := s.Idempotency.Complete(r.Context(), rec); err != nil {
+			s.Logger.For(r.Context()).Error("Idempotency store failed", zap.Error(err))
+			return
+		}
+		stored = true
+	})
+}
+
+func (s *Server) releaseIdempotencyKey(r *http.Request, key string) {
+	if err := s.Idempotency.Release(r.Context(), key); err != nil {
+		s.Logger.For(r.Context()).Error("Idempotency store failed", zap.Error(err))
+	}
+}
+
+// replay writes the stored response of rec, or the reason why it cannot
+func replay(w http.ResponseWriter, r *http.Request, rec idempotency.Record, fingerprint string) {
+	switch {
+	case rec.Fingerprint != fingerprint:
+		resp := ParseErrorCode(errors.NewCode(errcode.IdempotencyKeyReused, "the key was used with another method, URL or body"))
+		resp.Render(w, r)
+	case !rec.Completed:
+		w.Header().Set("Retry-After", "1")
+		resp := ParseErrorCode(errors.NewCode(errcode.IdempotencyInProgress, "retry later"))
+		resp.Render(w, r)
+	default:
+		if rec.ContentType != "" {
+			w.Header().Set("Content-Type", rec.ContentType)
+		}
+		w.Header().Set(replayedHeader, "true")
+		w.WriteHeader(rec.StatusCode)
+		w.Write(rec.Body)
+	}
+}
+
+// noStore reports whether the response headers h forbid keeping the response
+func noStore(h http.Header) bool {
+	for _, directive := range strings.Split(h.Get("Cache-Control"), ",") {
+		if strings.EqualFold(strings.TrimSpace(directive), "no-store") {
+			return true
+		}
+	}
+	return false
+}
+
+func unsafeMethod(method string) bool {
+	return method == http.MethodPost || method == http.MethodPut || method == http.MethodPatch
+}
+
+func validIdempotencyKey(key string) bool {
+	if len(key) > maxIdempotencyKey {
+		return false
+	}
+	for i := 0; i < len(key); i++ {
+		if key[i] < 0x20 || key[i] > 0x7e {
+			return false
+		}
+	}
+	return true
+}
+
+// hash is the hex SHA-256 of the parts separated by NUL bytes
+func hash(parts ...string) string {
+	h := sha256.New()
+	for i, p := range parts {
+		if i > 0 {
+			h.Write([]byte{0})
+		}
+		io.WriteString(h, p)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
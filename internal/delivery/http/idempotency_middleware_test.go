@@ -0,0 +1,144 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	idempotencyData "go-skeleton-auth/internal/data/idempotency"
+	"go-skeleton-auth/internal/entity/errcode"
+	"go-skeleton-auth/pkg/response"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestIdempotencyMiddleware(t *testing.T) {
+	var (
+		calls   int
+		blocked bool
+		s       = &Server{Idempotency: idempotencyData.NewMemory()}
+		inner   http.Handler
+	)
+	h := s.IdempotencyMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if blocked {
+			// a retry arrives while this request is in flight
+			blocked = false
+			inner.ServeHTTP(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"id":1}`))
+	}))
+
+	do := func(method, key, body string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(method, "/example/admin/tokens/revoke", strings.NewReader(body))
+		if key != "" {
+			r.Header.Set("Idempotency-Key", key)
+		}
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		return w
+	}
+	errorCode := func(w *httptest.ResponseRecorder) int {
+		var body response.Response
+		require.NoError(t, json.NewDecoder(w.Body).Decode(&body))
+		return body.Error.Code
+	}
+
+	w := do(http.MethodPost, "k1", `{"jti":"a"}`)
+	require.Equal(t, http.StatusCreated, w.Code)
+	require.Empty(t, w.Header().Get("Idempotent-Replayed"))
+
+	w = do(http.MethodPost, "k1", `{"jti":"a"}`)
+	require.Equal(t, http.StatusCreated, w.Code)
+	require.Equal(t, "true", w.Header().Get("Idempotent-Replayed"))
+	require.Equal(t, "application/json", w.Header().Get("Content-Type"))
+	require.Equal(t, `{"id":1}`, w.Body.String())
+	require.Equal(t, 1, calls)
+
+	w = do(http.MethodPost, "k1", `{"jti":"b"}`)
+	require.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	require.Equal(t, int(errcode.IdempotencyKeyReused), errorCode(w))
+
+	w = do(http.MethodPost, strings.Repeat("k", 256), `{}`)
+	require.Equal(t, http.StatusBadRequest, w.Code)
+	require.Equal(t, int(errcode.InvalidIdempotencyKey), errorCode(w))
+
+	// without a key, or on safe methods, every request goes through
+	do(http.MethodPost, "", `{"jti":"a"}`)
+	do(http.MethodGet, "k1", "")
+	require.Equal(t, 3, calls)
+
+	var retry *httptest.ResponseRecorder
+	inner = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		retry = do(http.MethodPost, "k2", `{}`)
+	})
+	blocked = true
+	do(http.MethodPost, "k2", `{}`)
+	require.Equal(t, http.StatusConflict, retry.Code)
+	require.Equal(t, int(errcode.IdempotencyInProgress), errorCode(retry))
+}
+
+func TestIdempotencyMiddlewareServerError(t *testing.T) {
+	var calls int
+	s := &Server{Idempotency: idempotencyData.NewMemory()}
+	h := s.IdempotencyMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+
+	for i := 0; i < 2; i++ {
+		r := httptest.NewRequest(http.MethodPost, "/example/admin/tokens/revoke", strings.NewReader(`{}`))
+		r.Header.Set("Idempotency-Key", "k1")
+		h.ServeHTTP(httptest.NewRecorder(), r)
+	}
+	require.Equal(t, 2, calls)
+}
+
+func TestIdempotencyMiddlewareNoStore(t *testing.T) {
+	var calls int
+	s := &Server{Idempotency: idempotencyData.NewMemory()}
+	h := s.IdempotencyMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("Cache-Control", "private, no-store")
+		w.Write([]byte(`{"key":"secret"}`))
+	}))
+
+	for i := 0; i < 2; i++ {
+		r := httptest.NewRequest(http.MethodPost, "/example/admin/apikeys", strings.NewReader(`{}`))
+		r.Header.Set("Idempotency-Key", "k1")
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		require.Empty(t, w.Header().Get("Idempotent-Replayed"))
+	}
+	// the secret is never replayed from the store
+	require.Equal(t, 2, calls)
+}
+
+func TestIdempotencyMiddlewareBodyLimit(t *testing.T) {
+	var calls int
+	s := &Server{Idempotency: idempotencyData.NewMemory(), IdempotencyMaxBody: 8}
+	h := s.IdempotencyMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+	}))
+
+	do := func(body string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodPost, "/example/admin/tokens/revoke", strings.NewReader(body))
+		r.Header.Set("Idempotency-Key", "k-"+body)
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		return w
+	}
+
+	require.Equal(t, http.StatusOK, do(`{"a":1}`).Code)
+	w := do(`{"a":"too large"}`)
+	require.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+	var body response.Response
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&body))
+	require.Equal(t, int(errcode.PayloadTooLarge), body.Error.Code)
+	require.Equal(t, 1, calls)
+}
@@ -4,52 +4,36 @@ import (
 	"context"
 	"fmt"
 	"go-skeleton-auth/internal/entity"
-	"go-skeleton-auth/pkg/response"
 	"net/http"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/dgrijalva/jwt-go"
 )
 
+// JWTMiddleware authenticates requests with an HS256 Bearer JWT.
+// Authentication failures are rendered as 401 with a WWW-Authenticate challenge.
 func (s *Server) JWTMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 
 		authorization := r.Header.Get("Authorization")
 		if authorization == "" {
-			resp := &response.Response{}
-			defer resp.RenderJSON(w, r)
-
-			resp.Error = response.Error{
-				Status: false,
-				Msg:    "Invalid token: unsupported token type",
-				Code:   403,
-			}
-
-			w.Header().Set("Content-Type", "application/json")
-			w.WriteHeader(403)
-
+			errMissingCredentials.render(w, r)
 			return
 		}
 
-		token := strings.Split(authorization, " ")
-		if token[0] != "Bearer" {
-			resp := &response.Response{}
-			defer resp.RenderJSON(w, r)
-
-			resp.Error = response.Error{
-				Status: false,
-				Msg:    "Invalid token: unsupported token type",
-				Code:   403,
-			}
-
-			w.Header().Set("Content-Type", "application/json")
-			w.WriteHeader(403)
-
+		token := strings.SplitN(authorization, " ", 2)
+		if !strings.EqualFold(token[0], "Bearer") {
+			errUnsupportedScheme.render(w, r)
+			return
+		}
+		if len(token) != 2 || strings.TrimSpace(token[1]) == "" {
+			errMalformedToken.render(w, r)
 			return
 		}
 
-		jwtToken, err := jwt.Parse(token[1], func(_token *jwt.Token) (interface{}, error) {
+		jwtToken, err := jwt.Parse(strings.TrimSpace(token[1]), func(_token *jwt.Token) (interface{}, error) {
 			if method, ok := _token.Method.(*jwt.SigningMethodHMAC); !ok {
 				return nil, fmt.Errorf("signing method invalid - a")
 			} else if method != jwt.SigningMethodHS256 {
@@ -59,65 +43,47 @@ func (s *Server) JWTMiddleware(next http.Handler) http.Handler {
 			return []byte(os.Getenv("TOKEN_SECRET")), nil
 		})
 		if err != nil {
-			resp := &response.Response{}
-			defer resp.RenderJSON(w, r)
-
-			resp.Error = response.Error{
-				Status: false,
-				Msg:    err.Error(),
-				Code:   500,
+			if ve, ok := err.(*jwt.ValidationError); ok && ve.Errors&jwt.ValidationErrorExpired != 0 {
+				errExpiredToken.render(w, r)
+				return
 			}
-
-			w.Header().Set("Content-Type", "application/json")
-			w.WriteHeader(500)
-
+			errInvalidToken.render(w, r)
 			return
 		}
 
 		claims, ok := jwtToken.Claims.(jwt.MapClaims)
-		if !ok {
-			resp := &response.Response{}
-			defer resp.RenderJSON(w, r)
-
-			resp.Error = response.Error{
-				Status: false,
-				Msg:    "Invalid token: unsupported token type",
-				Code:   401,
-			}
-
-			w.Header().Set("Content-Type", "application/json")
-			w.WriteHeader(401)
-
+		if !ok || !jwtToken.Valid {
+			errInvalidToken.render(w, r)
 			return
 		}
-		if !jwtToken.Valid {
-			resp := &response.Response{}
-			defer resp.RenderJSON(w, r)
 
-			resp.Error = response.Error{
-				Status: false,
-				Msg:    "Invalid token: unsupported token type",
-				Code:   401,
+		if s.Revocation != nil {
+			jti, _ := claims["jti"].(string)
+			sub, _ := claims["sub"].(string)
+			var issuedAt time.Time
+			if iat, ok := claims["iat"].(float64); ok {
+				issuedAt = time.Unix(int64(iat), 0)
 			}
 
-			w.Header().Set("Content-Type", "application/json")
-			w.WriteHeader(401)
-
-			return
+			revoked, err := s.Revocation.IsRevoked(r.Context(), jti, sub, issuedAt)
+			if err != nil {
+				errRevocationDown.render(w, r)
+				return
+			}
+			if revoked {
+				errRevokedToken.render(w, r)
+				return
+			}
 		}
 
 		// do something with decoded claims
-		for key, val := range claims {
-			if key != "permissions" {
-				continue
-			}
-			ctxVal := entity.ContextValue{
-				M: map[string]interface{}{
-					key: val,
-				},
-			}
-			r = r.WithContext(context.WithValue(r.Context(), entity.ContextKey("claims"), ctxVal))
+		// all claims are kept so the service layer can use them as principal attributes
+		ctxVal := entity.ContextValue{
+			M: map[string]interface{}(claims),
 		}
+		r = withClaims(r, ctxVal)
+		// the raw token lets the service layer check rights with the auth API
+		r = r.WithContext(context.WithValue(r.Context(), entity.ContextKey("token"), strings.TrimSpace(token[1])))
 
 		next.ServeHTTP(w, r)
 	})
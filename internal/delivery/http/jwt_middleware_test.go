@@ -0,0 +1,217 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"go-skeleton-auth/internal/entity"
+	"go-skeleton-auth/internal/entity/apikey"
+	"go-skeleton-auth/internal/entity/errcode"
+	"go-skeleton-auth/pkg/errors"
+	jaegerLog "go-skeleton-auth/pkg/log"
+	"go-skeleton-auth/pkg/response"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+const testSecret = "test-secret"
+
+type fakeRevocation struct {
+	revoked bool
+	err     error
+}
+
+func (f fakeRevocation) IsRevoked(ctx context.Context, jti, subject string, issuedAt time.Time) (bool, error) {
+	return f.revoked, f.err
+}
+
+type fakeAPIKey struct{}
+
+func (fakeAPIKey) Authenticate(ctx context.Context, key string) (apikey.APIKey, error) {
+	switch key {
+	case "valid-key":
+	case "lookup-fails":
+		return apikey.APIKey{}, errors.Wrap(errors.New("db down"), "[SERVICE][Authenticate]")
+	default:
+		return apikey.APIKey{}, errors.NewCode(errcode.InvalidAPIKey, "invalid API key")
+	}
+	return apikey.APIKey{ID: 7, Owner: "billing", Scopes: "skeleton:read"}, nil
+}
+
+func signToken(t *testing.T, method jwt.SigningMethod, key interface{}, claims jwt.MapClaims) string {
+	s, err := jwt.NewWithClaims(method, claims).SignedString(key)
+	require.NoError(t, err)
+	return s
+}
+
+func TestAuthMiddleware(t *testing.T) {
+	os.Setenv("TOKEN_SECRET", testSecret)
+	defer os.Unsetenv("TOKEN_SECRET")
+
+	var (
+		valid = signToken(t, jwt.SigningMethodHS256, []byte(testSecret), jwt.MapClaims{
+			"sub":         "user-1",
+			"exp":         time.Now().Add(time.Hour).Unix(),
+			"permissions": map[string]interface{}{"skeleton": []interface{}{"skeleton:read"}},
+		})
+		expired = signToken(t, jwt.SigningMethodHS256, []byte(testSecret), jwt.MapClaims{
+			"exp": time.Now().Add(-time.Hour).Unix(),
+		})
+		wrongSecret = signToken(t, jwt.SigningMethodHS256, []byte("other"), jwt.MapClaims{})
+		wrongAlg    = signToken(t, jwt.SigningMethodHS512, []byte(testSecret), jwt.MapClaims{})
+	)
+
+	testCases := []struct {
+		name       string
+		header     http.Header
+		revocation TokenRevocationChecker
+		status     int
+		code       errors.Code
+		challenge  bool
+		subject    string
+	}{
+		{
+			name:      "missing header",
+			header:    http.Header{},
+			status:    http.StatusUnauthorized,
+			code:      errcode.MissingCredentials,
+			challenge: true,
+		},
+		{
+			name:      "unsupported scheme",
+			header:    http.Header{"Authorization": {"Basic dXNlcjpwYXNz"}},
+			status:    http.StatusUnauthorized,
+			code:      errcode.UnsupportedScheme,
+			challenge: true,
+		},
+		{
+			name:      "bearer without token",
+			header:    http.Header{"Authorization": {"Bearer"}},
+			status:    http.StatusUnauthorized,
+			code:      errcode.InvalidToken,
+			challenge: true,
+		},
+		{
+			name:      "bearer with blank token",
+			header:    http.Header{"Authorization": {"Bearer   "}},
+			status:    http.StatusUnauthorized,
+			code:      errcode.InvalidToken,
+			challenge: true,
+		},
+		{
+			name:      "malformed token",
+			header:    http.Header{"Authorization": {"Bearer not.a.jwt"}},
+			status:    http.StatusUnauthorized,
+			code:      errcode.InvalidToken,
+			challenge: true,
+		},
+		{
+			name:      "bad signature",
+			header:    http.Header{"Authorization": {"Bearer " + wrongSecret}},
+			status:    http.StatusUnauthorized,
+			code:      errcode.InvalidToken,
+			challenge: true,
+		},
+		{
+			name:      "unexpected signing method",
+			header:    http.Header{"Authorization": {"Bearer " + wrongAlg}},
+			status:    http.StatusUnauthorized,
+			code:      errcode.InvalidToken,
+			challenge: true,
+		},
+		{
+			name:      "expired token",
+			header:    http.Header{"Authorization": {"Bearer " + expired}},
+			status:    http.StatusUnauthorized,
+			code:      errcode.ExpiredToken,
+			challenge: true,
+		},
+		{
+			name:       "revoked token",
+			header:     http.Header{"Authorization": {"Bearer " + valid}},
+			revocation: fakeRevocation{revoked: true},
+			status:     http.StatusUnauthorized,
+			code:       errcode.RevokedToken,
+			challenge:  true,
+		},
+		{
+			name:       "revocation check unavailable",
+			header:     http.Header{"Authorization": {"Bearer " + valid}},
+			revocation: fakeRevocation{err: errors.New("db down")},
+			status:     http.StatusServiceUnavailable,
+			code:       errcode.RevocationCheckDown,
+		},
+		{
+			name:    "valid token",
+			header:  http.Header{"Authorization": {"Bearer " + valid}},
+			status:  http.StatusOK,
+			subject: "user-1",
+		},
+		{
+			name:    "lowercase scheme",
+			header:  http.Header{"Authorization": {"bearer " + valid}},
+			status:  http.StatusOK,
+			subject: "user-1",
+		},
+		{
+			name:      "invalid API key",
+			header:    http.Header{"X-Api-Key": {"wrong-key"}},
+			status:    http.StatusUnauthorized,
+			code:      errcode.InvalidAPIKey,
+			challenge: true,
+		},
+		{
+			name:   "API key lookup failure",
+			header: http.Header{"X-Api-Key": {"lookup-fails"}},
+			status: http.StatusInternalServerError,
+			code:   errors.CodeUnknown,
+		},
+		{
+			name:    "valid API key",
+			header:  http.Header{"Authorization": {"ApiKey valid-key"}},
+			status:  http.StatusOK,
+			subject: "apikey:7",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			s := &Server{APIKey: fakeAPIKey{}, Revocation: tc.revocation, Logger: jaegerLog.NewFactory(zap.NewNop())}
+
+			var subject string
+			handler := s.AuthMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				claims := r.Context().Value(entity.ContextKey("claims")).(entity.ContextValue)
+				subject, _ = claims.Get("sub").(string)
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, "/example/skeleton", nil)
+			req.Header = tc.header
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			require.Equal(t, tc.status, rec.Code)
+			require.Equal(t, tc.subject, subject)
+			if tc.challenge {
+				require.Contains(t, rec.Header().Get("WWW-Authenticate"), `Bearer realm="example"`)
+			} else {
+				require.Empty(t, rec.Header().Get("WWW-Authenticate"))
+			}
+			if tc.status == http.StatusOK {
+				return
+			}
+
+			require.Contains(t, rec.Header().Get("Content-Type"), "application/json")
+			var body response.Response
+			require.NoError(t, json.NewDecoder(rec.Body).Decode(&body))
+			require.True(t, body.Error.Status)
+			require.Equal(t, int(tc.code), body.Error.Code)
+		})
+	}
+}
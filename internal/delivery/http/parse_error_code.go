@@ -1,38 +1,55 @@
 package http
 
 import (
+	"context"
+	"net/http"
+
+	"go-skeleton-auth/internal/entity/errcode"
+	"go-skeleton-auth/pkg/binding"
+	"go-skeleton-auth/pkg/compression"
+	"go-skeleton-auth/pkg/errors"
 	"go-skeleton-auth/pkg/response"
-	"strings"
 )
 
-// ParseErrorCode ...
-func ParseErrorCode(err string) response.Response {
-	errResp := response.Error{}
+// ParseErrorCode maps err to a response using the application error code it carries,
+// see internal/entity/errcode. Errors without a code are internal server errors.
+// Only the message of the code and the field errors of binding errors are sent,
+// the error itself is left to the logs.
+func ParseErrorCode(err error) response.Response {
+	code, info := errorInfo(err)
 
-	switch {
-	case strings.Contains(err, "401"):
-		errResp = response.Error{
-			Status: false,
-			Msg:    "Unauthorized",
-			Code:   401,
-		}
-	case strings.Contains(err, "10001"):
-		errResp = response.Error{
-			Status: false,
-			Msg:    "Failed to fetch data",
-			Code:   10001,
-		}
-	case strings.Contains(err, "10002"):
-		errResp = response.Error{
-			Status: false,
-			Msg:    "Failed to insert data",
-			Code:   10001,
-		}
+	return response.Response{
+		StatusCode: info.HTTPStatus,
+		Error: response.Error{
+			Status: true,
+			Msg:    info.Message,
+			Code:   int(code),
+			Fields: binding.FieldErrors(err),
+		},
 	}
+}
 
-	errResp.Msg = errResp.Msg + " | " + err
+// errorInfo returns the code of err and its registration
+func errorInfo(err error) (errors.Code, errors.CodeInfo) {
+	code, ok := errors.CodeOf(err)
+	switch {
+	case ok:
+	case errors.Is(err, context.DeadlineExceeded):
+		code = errcode.Timeout
+	case errors.Is(err, context.Canceled):
+		code = errcode.RequestCanceled
+	}
+	info, _ := errors.Lookup(code)
+	return code, info
+}
 
-	return response.Response{
-		Error: errResp,
+// CompressionError renders the request body errors of pkg/compression with
+// their application error codes, see compression.Options.ErrorHandler
+func CompressionError(w http.ResponseWriter, r *http.Request, err error) {
+	code := errcode.BadRequest
+	if errors.Is(err, compression.ErrUnsupportedEncoding) {
+		code = errcode.UnsupportedEncoding
 	}
+	resp := ParseErrorCode(errors.WithCode(err, code))
+	resp.Render(w, r)
 }
@@ -0,0 +1,75 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go-skeleton-auth/internal/entity/errcode"
+	"go-skeleton-auth/pkg/compression"
+	"go-skeleton-auth/pkg/errors"
+	"go-skeleton-auth/pkg/response"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseErrorCode(t *testing.T) {
+	testCases := []struct {
+		name   string
+		err    error
+		status int
+		code   errors.Code
+	}{
+		{
+			name:   "coded error",
+			err:    errors.NewCode(errcode.Forbidden, "forbidden"),
+			status: http.StatusForbidden,
+			code:   errcode.Forbidden,
+		},
+		{
+			name:   "wrapped coded error",
+			err:    errors.Wrap(errors.WithCode(errors.New("insert failed"), errcode.InsertFailed), "[SERVICE]"),
+			status: http.StatusInternalServerError,
+			code:   errcode.InsertFailed,
+		},
+		{
+			name:   "digits in message are not codes",
+			err:    errors.New("user 10001 not found, 401 attempts"),
+			status: http.StatusInternalServerError,
+			code:   errors.CodeUnknown,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			resp := ParseErrorCode(tc.err)
+			require.Equal(t, tc.status, resp.StatusCode)
+			require.Equal(t, int(tc.code), resp.Error.Code)
+			require.True(t, resp.Error.Status)
+
+			// the cause is logged, not sent
+			info, _ := errors.Lookup(tc.code)
+			require.Equal(t, info.Message, resp.Error.Msg)
+		})
+	}
+}
+
+func TestCompressionError(t *testing.T) {
+	h := compression.New(compression.Options{ErrorHandler: CompressionError}).Handler(http.NotFoundHandler())
+
+	for encoding, code := range map[string]errors.Code{
+		"br":   errcode.UnsupportedEncoding,
+		"gzip": errcode.BadRequest,
+	} {
+		r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader("not gzip"))
+		r.Header.Set("Content-Encoding", encoding)
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+
+		var body response.Response
+		require.NoError(t, json.NewDecoder(w.Body).Decode(&body))
+		require.Equal(t, int(code), body.Error.Code)
+	}
+}
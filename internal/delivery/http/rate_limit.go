@@ -0,0 +1,89 @@
+package http
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"go-skeleton-auth/internal/entity"
+	"go-skeleton-auth/internal/entity/errcode"
+	"go-skeleton-auth/pkg/errors"
+	"go-skeleton-auth/pkg/ratelimit"
+	"go-skeleton-auth/pkg/response"
+
+	"go.uber.org/zap"
+)
+
+// IPRateLimitMiddleware limits the requests of each client IP. It runs before
+// authentication so that requests with invalid credentials, and the API key
+// lookups they cause, are limited too.
+// It lets requests through when the store fails.
+func (s *Server) IPRateLimitMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.limit(w, r, next, s.IPRateLimiter, "ip:"+s.clientIP(r))
+	})
+}
+
+// RateLimitMiddleware limits the requests of each authenticated principal,
+// see principal. It lets requests through when the store fails.
+func (s *Server) RateLimitMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.limit(w, r, next, s.RateLimiter, s.principal(r))
+	})
+}
+
+// limit takes a token of key from limiter before serving next,
+// requests are not limited when limiter is nil
+func (s *Server) limit(w http.ResponseWriter, r *http.Request, next http.Handler, limiter *ratelimit.Limiter, key string) {
+	if limiter == nil {
+		next.ServeHTTP(w, r)
+		return
+	}
+
+	res, ok, err := limiter.Take(r.Context(), r.URL.Path, key)
+	if err != nil {
+		s.Logger.For(r.Context()).Error("Rate limit store failed", zap.Error(err))
+	}
+	if !ok {
+		next.ServeHTTP(w, r)
+		return
+	}
+
+	h := w.Header()
+	h.Set("X-RateLimit-Limit", strconv.Itoa(res.Limit))
+	h.Set("X-RateLimit-Remaining", strconv.Itoa(res.Remaining))
+	h.Set("X-RateLimit-Reset", ceilSeconds(res.Reset))
+	if res.Allowed {
+		next.ServeHTTP(w, r)
+		return
+	}
+
+	h.Set("Retry-After", ceilSeconds(res.RetryAfter))
+	info, _ := errors.Lookup(errcode.RateLimited)
+	resp := response.Response{
+		StatusCode: info.HTTPStatus,
+		Error: response.Error{
+			Status: true,
+			Msg:    info.Message,
+			Code:   int(errcode.RateLimited),
+		},
+	}
+	resp.Render(w, r)
+}
+
+// principal is the key of the buckets of an authenticated request: the
+// subject of the JWT, API key or client certificate, and the client IP
+// for JWTs without a subject
+func (s *Server) principal(r *http.Request) string {
+	if claims, ok := r.Context().Value(entity.ContextKey("claims")).(entity.ContextValue); ok {
+		if sub, ok := claims.Get("sub").(string); ok && sub != "" {
+			return "sub:" + sub
+		}
+	}
+	return "ip:" + s.clientIP(r)
+}
+
+func ceilSeconds(d time.Duration) string {
+	return strconv.Itoa(int(math.Ceil(d.Seconds())))
+}
@@ -0,0 +1,80 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"go-skeleton-auth/internal/entity"
+	"go-skeleton-auth/internal/entity/errcode"
+	jaegerLog "go-skeleton-auth/pkg/log"
+	"go-skeleton-auth/pkg/ratelimit"
+	"go-skeleton-auth/pkg/response"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestRateLimitMiddleware(t *testing.T) {
+	s := &Server{RateLimiter: ratelimit.New(ratelimit.NewMemoryStore(), ratelimit.Limit{Requests: 1, Per: time.Minute})}
+	h := s.RateLimitMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	do := func(sub string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodGet, "/example/skeleton", nil)
+		if sub != "" {
+			r = r.WithContext(context.WithValue(r.Context(), entity.ContextKey("claims"),
+				entity.ContextValue{M: map[string]interface{}{"sub": sub}}))
+		}
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		return w
+	}
+
+	w := do("user-1")
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Equal(t, "1", w.Header().Get("X-RateLimit-Limit"))
+	require.Equal(t, "0", w.Header().Get("X-RateLimit-Remaining"))
+	require.Equal(t, "60", w.Header().Get("X-RateLimit-Reset"))
+
+	w = do("user-1")
+	require.Equal(t, http.StatusTooManyRequests, w.Code)
+	require.Equal(t, "60", w.Header().Get("Retry-After"))
+	var body response.Response
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&body))
+	require.True(t, body.Error.Status)
+	require.Equal(t, int(errcode.RateLimited), body.Error.Code)
+
+	// other principals, and anonymous clients by IP, have their own quota
+	require.Equal(t, http.StatusOK, do("user-2").Code)
+	require.Equal(t, http.StatusOK, do("").Code)
+	require.Equal(t, http.StatusTooManyRequests, do("").Code)
+}
+
+func TestIPRateLimitBeforeAuth(t *testing.T) {
+	s := &Server{
+		APIKey:        fakeAPIKey{},
+		Logger:        jaegerLog.NewFactory(zap.NewNop()),
+		IPRateLimiter: ratelimit.New(ratelimit.NewMemoryStore(), ratelimit.Limit{Requests: 2, Per: time.Minute}),
+	}
+	h := s.IPRateLimitMiddleware(s.AuthMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})))
+
+	do := func(remote string) int {
+		r := httptest.NewRequest(http.MethodGet, "/example/skeleton", nil)
+		r.RemoteAddr = remote
+		r.Header.Set("X-API-Key", "wrong-key")
+		// spoofed, the peer is not a trusted proxy
+		r.Header.Set("X-Forwarded-For", "198.51.100.1")
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		return w.Code
+	}
+
+	// invalid credentials are limited too
+	require.Equal(t, http.StatusUnauthorized, do("203.0.113.7:1"))
+	require.Equal(t, http.StatusUnauthorized, do("203.0.113.7:2"))
+	require.Equal(t, http.StatusTooManyRequests, do("203.0.113.7:3"))
+	require.Equal(t, http.StatusUnauthorized, do("203.0.113.8:1"))
+}
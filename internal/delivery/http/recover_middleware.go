@@ -0,0 +1,63 @@
+package http
+
+import (
+	"expvar"
+	"fmt"
+	"net/http"
+	"runtime/debug"
+
+	"go-skeleton-auth/internal/entity/errcode"
+	"go-skeleton-auth/pkg/errors"
+
+	"github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/ext"
+	otlog "github.com/opentracing/opentracing-go/log"
+	"go.uber.org/zap"
+)
+
+// panics counts the recovered panics, it is exposed to authenticated callers on /debug/vars
+var panics = expvar.NewInt("http_panics_total")
+
+// RecoverMiddleware recovers the panics of the handlers it wraps: the panic and
+// its stack are logged, the span of TracingMiddleware is marked as errored and a 500 response with
+// errcode.Panic is rendered, unless the handler already started its response.
+func (s *Server) RecoverMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rw := newResponseWriter(w)
+
+		defer func() {
+			rec := recover()
+			if rec == nil {
+				return
+			}
+			// net/http aborts the response silently for this one
+			if rec == http.ErrAbortHandler {
+				panic(rec)
+			}
+			panics.Add(1)
+
+			stack := string(debug.Stack())
+			ctx := r.Context()
+
+			if span := opentracing.SpanFromContext(ctx); span != nil {
+				ext.Error.Set(span, true)
+				span.LogFields(otlog.String("event", "panic"), otlog.String("message", fmt.Sprint(rec)))
+			}
+
+			s.Logger.For(ctx).Error("HTTP handler panic",
+				zap.String("method", r.Method),
+				zap.Stringer("url", r.URL),
+				zap.String("panic", fmt.Sprint(rec)),
+				zap.String("stack", stack),
+			)
+
+			if rw.wroteHeader() {
+				return
+			}
+			resp := ParseErrorCode(errors.NewCode(errcode.Panic, "panic"))
+			resp.Render(rw, r)
+		}()
+
+		next.ServeHTTP(rw, r)
+	})
+}
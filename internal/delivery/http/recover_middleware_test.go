@@ -0,0 +1,77 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go-skeleton-auth/internal/entity"
+	"go-skeleton-auth/internal/entity/errcode"
+	jaegerLog "go-skeleton-auth/pkg/log"
+	"go-skeleton-auth/pkg/response"
+
+	"github.com/opentracing/opentracing-go/mocktracer"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestRecoverMiddleware(t *testing.T) {
+	core, logs := observer.New(zap.InfoLevel)
+	tracer := mocktracer.New()
+	s := &Server{Tracer: tracer, Logger: jaegerLog.NewFactory(zap.New(core))}
+
+	before := panics.Value()
+	h := s.TracingMiddleware(s.RecoverMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var m map[string]interface{}
+		_ = m["permissions"].(map[string]interface{})
+	})))
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/example/skeleton", nil))
+
+	require.Equal(t, http.StatusInternalServerError, w.Code)
+	var body response.Response
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&body))
+	require.Equal(t, int(errcode.Panic), body.Error.Code)
+	require.NotContains(t, body.Error.Msg, "interface conversion")
+
+	require.Equal(t, before+1, panics.Value())
+	require.Equal(t, true, tracer.FinishedSpans()[0].Tag("error"))
+	entry := logs.FilterMessage("HTTP handler panic").All()[0]
+	require.Contains(t, entry.ContextMap()["stack"], "TestRecoverMiddleware")
+
+	// a typed handler asserting claims the request does not carry, like checkPermission did
+	h = s.RecoverMiddleware(Handle("GetSkeleton", tracer, s.Logger, func(ctx context.Context, req struct{}) (struct{}, struct{}, error) {
+		claims := ctx.Value(entity.ContextKey("claims")).(entity.ContextValue)
+		_ = claims.Get("permissions").(map[string]interface{})
+		return struct{}{}, struct{}{}, nil
+	}))
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/example/skeleton", nil))
+
+	require.Equal(t, http.StatusInternalServerError, w.Code)
+	require.NotContains(t, w.Body.String(), "interface conversion")
+	body = response.Response{}
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&body))
+	require.Equal(t, int(errcode.Panic), body.Error.Code)
+	require.Contains(t, logs.FilterMessage("HTTP handler panic").All()[1].ContextMap()["panic"], "interface conversion")
+
+	// a started response is left alone
+	h = s.RecoverMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusAccepted)
+		panic("late")
+	}))
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	require.Equal(t, http.StatusAccepted, w.Code)
+	require.Empty(t, w.Body.String())
+
+	require.Panics(t, func() {
+		s.RecoverMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			panic(http.ErrAbortHandler)
+		})).ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	})
+}
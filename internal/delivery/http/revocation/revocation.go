@@ -0,0 +1,33 @@
+package revocation
+
+import (
+	"context"
+
+	"go-skeleton-auth/internal/entity/revocation"
+	jaegerLog "go-skeleton-auth/pkg/log"
+
+	"github.com/opentracing/opentracing-go"
+)
+
+// IRevocationSvc is an interface to Revocation Service
+type IRevocationSvc interface {
+	RevokeToken(ctx context.Context, req revocation.RevokeRequest) (revocation.Revocation, error)
+}
+
+type (
+	// Handler ...
+	Handler struct {
+		revocationSvc IRevocationSvc
+		tracer        opentracing.Tracer
+		logger        jaegerLog.Factory
+	}
+)
+
+// New for bridging revocation handler initialization
+func New(is IRevocationSvc, tracer opentracing.Tracer, logger jaegerLog.Factory) *Handler {
+	return &Handler{
+		revocationSvc: is,
+		tracer:        tracer,
+		logger:        logger,
+	}
+}
@@ -0,0 +1,29 @@
+package revocation
+
+import (
+	"context"
+	httpHelper "go-skeleton-auth/internal/delivery/http"
+	"go-skeleton-auth/internal/entity/revocation"
+	"net/http"
+)
+
+// RevokeToken godoc
+// @Summary Revoke tokens
+// @Description Revoke a token by jti, or every token of a subject issued before a time
+// @Tags Admin
+// @Accept  json
+// @Produce  json
+// @Security BearerAuth
+// @Param body body revocation.RevokeRequest true "Revocation"
+// @Success 200 {object} revocation.Revocation
+// @Failure 400 {object} response.Response
+// @Failure 422 {object} response.Response
+// @Router /admin/tokens/revoke [post]
+func (h *Handler) RevokeToken(w http.ResponseWriter, r *http.Request) {
+	httpHelper.Handle("RevokeToken", h.tracer, h.logger, h.revokeToken)(w, r)
+}
+
+func (h *Handler) revokeToken(ctx context.Context, req revocation.RevokeRequest) (revocation.Revocation, struct{}, error) {
+	result, err := h.revocationSvc.RevokeToken(ctx, req)
+	return result, struct{}{}, err
+}
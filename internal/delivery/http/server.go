@@ -1,11 +1,22 @@
 package http
 
 import (
+	"context"
+	"crypto/tls"
 	"net/http"
+	"net/netip"
+	"time"
 
+	"go-skeleton-auth/internal/entity/idempotency"
+	"go-skeleton-auth/pkg/compression"
+	"go-skeleton-auth/pkg/corspolicy"
 	"go-skeleton-auth/pkg/grace"
+	"go-skeleton-auth/pkg/health"
+	jaegerLog "go-skeleton-auth/pkg/log"
+	"go-skeleton-auth/pkg/ratelimit"
+	"go-skeleton-auth/pkg/requestid"
 
-	"github.com/rs/cors"
+	"github.com/opentracing/opentracing-go"
 )
 
 // SkeletonHandler ...
@@ -13,14 +24,100 @@ type SkeletonHandler interface {
 	GetSkeleton(w http.ResponseWriter, r *http.Request)
 }
 
+// RevocationHandler ...
+type RevocationHandler interface {
+	RevokeToken(w http.ResponseWriter, r *http.Request)
+}
+
+// APIKeyHandler ...
+type APIKeyHandler interface {
+	CreateAPIKey(w http.ResponseWriter, r *http.Request)
+	RotateAPIKey(w http.ResponseWriter, r *http.Request)
+}
+
+// TokenRevocationChecker ...
+type TokenRevocationChecker interface {
+	IsRevoked(ctx context.Context, jti, subject string, issuedAt time.Time) (bool, error)
+}
+
+// IdempotencyStore keeps the requests made with an Idempotency-Key and their responses,
+// see internal/data/idempotency
+type IdempotencyStore interface {
+	Reserve(ctx context.Context, rec idempotency.Record) (existing idempotency.Record, reserved bool, err error)
+	Complete(ctx context.Context, rec idempotency.Record) error
+	Release(ctx context.Context, key string) error
+}
+
 // Server ...
 type Server struct {
 	server   *http.Server
 	Skeleton SkeletonHandler
+	APIKey   APIKeyAuthenticator
+	// APIKeyHandler serves the admin endpoints creating and rotating API keys
+	APIKeyHandler APIKeyHandler
+	// Revocation is consulted by JWTMiddleware when set
+	Revocation        TokenRevocationChecker
+	RevocationHandler RevocationHandler
+	// BasePath is the prefix of the API routes, /example when empty
+	BasePath string
+	// Versions are the API versions served under BasePath, see VersionMiddleware
+	Versions VersionOptions
+	// Health checks the dependencies for /readyz, it is drained on shutdown
+	Health *health.Checker
+	// TLSConfig enables HTTPS, see pkg/tlsutil
+	TLSConfig *tls.Config
+	// TrustedProxies are the peers whose X-Forwarded-For is believed,
+	// the header is ignored when the peer is not one of them
+	TrustedProxies []netip.Prefix
+
+	Tracer    opentracing.Tracer
+	Logger    jaegerLog.Factory
+	AccessLog AccessLogOptions
+	// Timeouts are the deadlines of the requests, see TimeoutMiddleware
+	Timeouts TimeoutOptions
+	// ServerTimeouts are the timeouts of the connections
+	ServerTimeouts grace.Timeouts
+	// Compression compresses responses and decompresses gzip requests when set
+	Compression *compression.Compressor
+	// CORS is the CORS policy, cross-origin requests are not allowed when nil
+	CORS *corspolicy.CORS
+	// IPRateLimiter limits the requests of each client IP before authentication,
+	// nothing is limited when nil
+	IPRateLimiter *ratelimit.Limiter
+	// RateLimiter limits the requests of each principal after authentication,
+	// nothing is limited when nil
+	RateLimiter *ratelimit.Limiter
+	// Idempotency enables Idempotency-Key on POST, PUT and PATCH when set
+	Idempotency IdempotencyStore
+	// IdempotencyTTL is how long responses are replayed, 24h when 0
+	IdempotencyTTL time.Duration
+	// IdempotencyMaxBody limits the size of the bodies of requests with an
+	// Idempotency-Key, 10 MiB when 0
+	IdempotencyMaxBody int64
 }
 
 // Serve is serving HTTP gracefully on port x ...
 func (s *Server) Serve(port string) error {
-	handler := cors.AllowAll().Handler(s.Handler())
-	return grace.Serve(port, handler)
+	var handler http.Handler = s.Handler()
+	handler = s.RecoverMiddleware(handler)
+	if s.Compression != nil {
+		handler = s.Compression.Handler(handler)
+	}
+	handler = s.TracingMiddleware(handler)
+	handler = s.AccessLogMiddleware(handler)
+	handler = requestid.Middleware(handler)
+	if s.CORS != nil {
+		handler = s.CORS.Handler(handler)
+	}
+	handler = s.VersionMiddleware(handler)
+
+	opts := []grace.Option{grace.WithTimeouts(s.ServerTimeouts)}
+	if s.Health != nil {
+		// fail readiness while draining
+		opts = append(opts, grace.WithOnShutdown(s.Health.Drain))
+	}
+	if s.TLSConfig != nil {
+		opts = append(opts, grace.WithTLSConfig(s.TLSConfig))
+	}
+	return grace.Serve(port, handler, opts...)
 }
@@ -1,58 +1,59 @@
 package skeleton
 
 import (
+	"context"
 	httpHelper "go-skeleton-auth/internal/delivery/http"
-	"go-skeleton-auth/pkg/response"
-	"log"
+	"go-skeleton-auth/internal/entity/errcode"
+	"go-skeleton-auth/internal/entity/skeleton"
+	"go-skeleton-auth/pkg/errors"
+	"go-skeleton-auth/pkg/pagination"
 	"net/http"
-
-	"github.com/opentracing/opentracing-go"
-	"github.com/opentracing/opentracing-go/ext"
-	"go.uber.org/zap"
+	"net/url"
 )
 
+// listRequest is a list request, bound by pagination
+type listRequest struct {
+	pagination.Query
+	// url builds the links of the metadata
+	url *url.URL
+}
+
+// Bind implements httpHelper.Binder
+func (req *listRequest) Bind(r *http.Request) error {
+	q, err := pagination.Parse(r, listOptions)
+	if err != nil {
+		return errors.WithCode(err, errcode.BadRequest)
+	}
+	req.Query, req.url = q, httpHelper.OriginalURL(r)
+	return nil
+}
+
 // GetSkeleton godoc
 // @Summary Get entries of all skeletons
 // @Description Get entries of all skeletons
+// @Description Paged with page/limit or cursor, sorted with sort=-created_at,skeleton_name
+// @Description and filtered with filter=field:op:value (eq, ne, gt, gte, lt, lte, like, in)
 // @Tags Skeleton
 // @Accept  json
 // @Produce  json
 // @Security BearerAuth
+// @Param page query int false "Page number, starting at 1"
+// @Param limit query int false "Page size"
+// @Param cursor query string false "Cursor of the page, empty for the first one"
+// @Param sort query string false "Sort fields, - for descending"
+// @Param filter query []string false "Filter expressions field:op:value"
 // @Success 200
-// @Router /v1/profiles [get]
+// @Router /skeleton [get]
 func (h *Handler) GetSkeleton(w http.ResponseWriter, r *http.Request) {
-	var (
-		result   interface{}
-		metadata interface{}
-		err      error
-		resp     response.Response
-	)
-	defer resp.RenderJSON(w, r)
-
-	spanCtx, _ := h.tracer.Extract(opentracing.HTTPHeaders, opentracing.HTTPHeadersCarrier(r.Header))
-	span := h.tracer.StartSpan("GetSkeleton", ext.RPCServerOption(spanCtx))
-	defer span.Finish()
-
-	ctx := r.Context()
-	ctx = opentracing.ContextWithSpan(ctx, span)
-	h.logger.For(ctx).Info("HTTP request received", zap.String("method", r.Method), zap.Stringer("url", r.URL))
+	httpHelper.Handle("GetSkeleton", h.tracer, h.logger, h.getSkeleton)(w, r)
+}
 
+func (h *Handler) getSkeleton(ctx context.Context, req listRequest) ([]skeleton.Skeleton, pagination.Meta, error) {
 	// Your code here
-	err = h.skeletonSvc.GetSkeleton(ctx)
-	//
-
+	result, page, err := h.skeletonSvc.GetSkeleton(ctx, req.Query)
 	if err != nil {
-		resp = httpHelper.ParseErrorCode(err.Error())
-		//
-		log.Printf("[ERROR] %s %s - %v\n", r.Method, r.URL, err)
-		h.logger.For(ctx).Error("HTTP request error", zap.String("method", r.Method), zap.Stringer("url", r.URL), zap.Error(err))
-		return
+		return nil, pagination.Meta{}, err
 	}
 
-	resp.Data = result
-	resp.Metadata = metadata
-	log.Printf("[INFO] %s %s\n", r.Method, r.URL)
-	h.logger.For(ctx).Info("HTTP request done", zap.String("method", r.Method), zap.Stringer("url", r.URL))
-
-	return
+	return result, pagination.NewMeta(req.url, req.Query, page), nil
 }
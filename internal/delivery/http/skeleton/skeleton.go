@@ -3,7 +3,9 @@ package skeleton
 import (
 	"context"
 
+	"go-skeleton-auth/internal/entity/skeleton"
 	jaegerLog "go-skeleton-auth/pkg/log"
+	"go-skeleton-auth/pkg/pagination"
 
 	"github.com/opentracing/opentracing-go"
 )
@@ -11,7 +13,15 @@ import (
 // ISkeletonSvc is an interface to Skeleton Service
 // Masukkan function dari service ke dalam interface ini
 type ISkeletonSvc interface {
-	GetSkeleton(ctx context.Context) error
+	GetSkeleton(ctx context.Context, q pagination.Query) ([]skeleton.Skeleton, pagination.Result, error)
+}
+
+// listOptions are the fields skeleton lists can be sorted and filtered by
+var listOptions = pagination.Options{
+	Sortable:    []string{"skeleton_id", "skeleton_name", "created_at"},
+	Filterable:  []string{"skeleton_id", "skeleton_name", "created_at"},
+	DefaultSort: []pagination.Sort{{Field: "created_at", Desc: true}},
+	Key:         "skeleton_id",
 }
 
 type (
@@ -0,0 +1,60 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/swaggo/swag"
+)
+
+// swaggerDoc serves the swagger document of the version of the request.
+// The document generated by swag lists the operations of every version under
+// their versioned path, e.g. @Router /v2/skeleton [get]; the document of a
+// version keeps its operations and the unversioned ones, under a versioned base path.
+func swaggerDoc(w http.ResponseWriter, r *http.Request) {
+	doc, err := swag.ReadDoc()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	b, err := versionDoc([]byte(doc), VersionFrom(r.Context()))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.Write(b)
+}
+
+// versionDoc returns the document of version from the document of every version
+func versionDoc(doc []byte, version string) ([]byte, error) {
+	var spec map[string]interface{}
+	if err := json.Unmarshal(doc, &spec); err != nil {
+		return nil, err
+	}
+	if version == "" {
+		return doc, nil
+	}
+
+	paths, _ := spec["paths"].(map[string]interface{})
+	versioned := make(map[string]interface{}, len(paths))
+	for path, item := range paths {
+		if m := versionSegment.FindStringSubmatch(path); m != nil {
+			if m[1] != version {
+				continue
+			}
+			path = strings.TrimPrefix(path, "/"+version)
+		}
+		versioned[path] = item
+	}
+	spec["paths"] = versioned
+
+	basePath, _ := spec["basePath"].(string)
+	spec["basePath"] = strings.TrimSuffix(basePath, "/") + "/" + version
+	if info, ok := spec["info"].(map[string]interface{}); ok {
+		info["version"] = version
+	}
+	return json.Marshal(spec)
+}
@@ -0,0 +1,175 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"runtime/debug"
+	"strings"
+	"sync"
+	"time"
+
+	"go-skeleton-auth/internal/entity/errcode"
+	"go-skeleton-auth/pkg/errors"
+
+	"go.uber.org/zap"
+)
+
+// TimeoutOptions ...
+type TimeoutOptions struct {
+	// Default is the time given to the handlers, no limit when 0
+	Default time.Duration
+	// Routes override the default for path prefixes
+	Routes []TimeoutRoute
+}
+
+// TimeoutRoute ...
+type TimeoutRoute struct {
+	PathPrefix string
+	// Timeout is the time given to the handlers, no limit when 0
+	Timeout time.Duration
+}
+
+// timeout returns the timeout of the longest route prefix matching path, or the default
+func (o TimeoutOptions) timeout(path string) time.Duration {
+	d, matched := o.Default, -1
+	for _, route := range o.Routes {
+		if len(route.PathPrefix) > matched && strings.HasPrefix(path, route.PathPrefix) {
+			d, matched = route.Timeout, len(route.PathPrefix)
+		}
+	}
+	return d
+}
+
+// TimeoutMiddleware gives the request context a deadline, which the data layer and
+// the HTTP client honour. When the handler has not answered by the deadline it
+// answers 504, or 503 when the request is canceled, and discards what the
+// handler writes afterwards. A handler panicking after that is only logged.
+func (s *Server) TimeoutMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		d := s.Timeouts.timeout(r.URL.Path)
+		if d <= 0 {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(r.Context(), d)
+		defer cancel()
+
+		// the handler may outlive the request: it records into a copy of the
+		// request info, which is only merged back when it returns in time
+		info := infoFrom(ctx)
+		handlerInfo := *info
+		r = r.WithContext(context.WithValue(ctx, requestInfoKey{}, &handlerInfo))
+
+		var (
+			tw       = &timeoutWriter{header: make(http.Header)}
+			done     = make(chan struct{})
+			panicked = make(chan interface{}, 1)
+		)
+		go func() {
+			defer func() {
+				if p := recover(); p != nil {
+					tw.mu.Lock()
+					defer tw.mu.Unlock()
+					if tw.timedOut {
+						s.logLatePanic(r, p)
+						return
+					}
+					panicked <- p
+				}
+			}()
+			next.ServeHTTP(tw, r)
+			close(done)
+		}()
+
+		select {
+		case p := <-panicked:
+			*info = handlerInfo
+			// RecoverMiddleware handles it
+			panic(p)
+		case <-done:
+			*info = handlerInfo
+			tw.mu.Lock()
+			defer tw.mu.Unlock()
+			tw.writeTo(w)
+		case <-ctx.Done():
+			tw.mu.Lock()
+			tw.timedOut = true
+			tw.mu.Unlock()
+			// the handler panicked before it was marked as late
+			select {
+			case p := <-panicked:
+				*info = handlerInfo
+				panic(p)
+			default:
+			}
+
+			code := errcode.Timeout
+			if ctx.Err() == context.Canceled {
+				code = errcode.RequestCanceled
+			}
+			resp := ParseErrorCode(errors.WithCode(ctx.Err(), code))
+			resp.Render(w, r)
+		}
+	})
+}
+
+// logLatePanic logs the panic of a handler that outlived its request,
+// nothing recovers it otherwise
+func (s *Server) logLatePanic(r *http.Request, p interface{}) {
+	panics.Add(1)
+	s.Logger.Bg().Error("HTTP handler panic after timeout",
+		zap.String("method", r.Method),
+		zap.Stringer("url", r.URL),
+		zap.String("panic", fmt.Sprint(p)),
+		zap.String("stack", string(debug.Stack())),
+	)
+}
+
+// timeoutWriter buffers the response until the handler returns in time
+type timeoutWriter struct {
+	mu       sync.Mutex
+	header   http.Header
+	buf      bytes.Buffer
+	status   int
+	timedOut bool
+}
+
+func (tw *timeoutWriter) Header() http.Header {
+	return tw.header
+}
+
+func (tw *timeoutWriter) WriteHeader(status int) {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+	if tw.timedOut || tw.status != 0 {
+		return
+	}
+	tw.status = status
+}
+
+func (tw *timeoutWriter) Write(b []byte) (int, error) {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+	if tw.timedOut {
+		return 0, http.ErrHandlerTimeout
+	}
+	if tw.status == 0 {
+		tw.status = http.StatusOK
+	}
+	return tw.buf.Write(b)
+}
+
+func (tw *timeoutWriter) writeTo(w http.ResponseWriter) {
+	dst := w.Header()
+	for k, vv := range tw.header {
+		dst[k] = vv
+	}
+	if tw.status == 0 {
+		tw.status = http.StatusOK
+	}
+	w.WriteHeader(tw.status)
+	w.Write(tw.buf.Bytes())
+}
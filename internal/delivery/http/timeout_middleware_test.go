@@ -0,0 +1,106 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"go-skeleton-auth/internal/entity"
+	"go-skeleton-auth/internal/entity/errcode"
+	"go-skeleton-auth/pkg/errors"
+	jaegerLog "go-skeleton-auth/pkg/log"
+	"go-skeleton-auth/pkg/response"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestTimeoutMiddleware(t *testing.T) {
+	s := &Server{Timeouts: TimeoutOptions{
+		Default: 20 * time.Millisecond,
+		Routes: []TimeoutRoute{
+			{PathPrefix: "/example", Timeout: time.Second},
+			{PathPrefix: "/example/slow", Timeout: 0},
+		},
+	}}
+	require.Equal(t, 20*time.Millisecond, s.Timeouts.timeout("/debug/vars"))
+	require.Equal(t, time.Second, s.Timeouts.timeout("/example/skeleton"))
+	require.Zero(t, s.Timeouts.timeout("/example/slow/report"))
+
+	var deadline bool
+	h := s.TimeoutMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/example/skeleton" {
+			_, deadline = r.Context().Deadline()
+			w.Header().Set("X-Handler", "1")
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte("ok"))
+			return
+		}
+		<-r.Context().Done()
+		time.Sleep(5 * time.Millisecond)
+		w.Write([]byte("too late"))
+	}))
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/example/skeleton", nil))
+	require.True(t, deadline)
+	require.Equal(t, http.StatusCreated, w.Code)
+	require.Equal(t, "1", w.Header().Get("X-Handler"))
+	require.Equal(t, "ok", w.Body.String())
+
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	require.Equal(t, http.StatusGatewayTimeout, w.Code)
+	var body response.Response
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&body))
+	require.Equal(t, int(errcode.Timeout), body.Error.Code)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil).WithContext(ctx))
+	require.Equal(t, http.StatusServiceUnavailable, w.Code)
+}
+
+func TestTimeoutMiddlewareLateHandler(t *testing.T) {
+	core, logs := observer.New(zap.InfoLevel)
+	s := &Server{
+		Timeouts: TimeoutOptions{Default: 10 * time.Millisecond},
+		Logger:   jaegerLog.NewFactory(zap.New(core)),
+	}
+
+	finished := make(chan struct{})
+	h := s.AccessLogMiddleware(s.TimeoutMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer close(finished)
+		<-r.Context().Done()
+		time.Sleep(5 * time.Millisecond)
+		// authenticated too late, while the access log is written (go test -race)
+		withClaims(r, entity.ContextValue{M: map[string]interface{}{"sub": "late"}})
+		panic("late")
+	})))
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	require.Equal(t, http.StatusGatewayTimeout, w.Code)
+	<-finished
+
+	require.Eventually(t, func() bool {
+		return logs.FilterMessage("HTTP handler panic after timeout").Len() == 1
+	}, time.Second, time.Millisecond)
+	access := logs.FilterMessage("HTTP access").All()
+	require.Len(t, access, 1)
+	require.Equal(t, "", access[0].ContextMap()["user_id"])
+}
+
+func TestParseErrorCodeContext(t *testing.T) {
+	resp := ParseErrorCode(errors.Wrap(context.DeadlineExceeded, "[DATA][GetSkeletons]"))
+	require.Equal(t, http.StatusGatewayTimeout, resp.StatusCode)
+	require.Equal(t, int(errcode.Timeout), resp.Error.Code)
+
+	resp = ParseErrorCode(errors.Wrap(context.Canceled, "[DATA][GetSkeletons]"))
+	require.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+}
@@ -0,0 +1,53 @@
+package http
+
+import (
+	"net/http"
+
+	"go-skeleton-auth/pkg/tracing"
+
+	"github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/ext"
+)
+
+// TracingMiddleware starts the server span of every request, continuing the trace of
+// the caller when the request carries one. Handlers find the span in r.Context().
+// The span is named after the method and the mux route template, e.g.
+// "HTTP GET /example/skeleton", and tagged with the status, route, API version and user.
+func (s *Server) TracingMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		spanCtx, _ := s.Tracer.Extract(opentracing.HTTPHeaders, opentracing.HTTPHeadersCarrier(r.Header))
+		// renamed once mux matched the route
+		span := s.Tracer.StartSpan("HTTP "+r.Method, ext.RPCServerOption(spanCtx))
+		defer span.Finish()
+
+		ext.Component.Set(span, "net/http")
+		ext.HTTPMethod.Set(span, r.Method)
+		ext.HTTPUrl.Set(span, r.URL.String())
+
+		ctx, info := withRequestInfo(opentracing.ContextWithSpan(r.Context(), span))
+		info.traceID = tracing.TraceID(span)
+
+		rw := newResponseWriter(w)
+		next.ServeHTTP(rw, r.WithContext(ctx))
+
+		if info.route != "" {
+			span.SetOperationName("HTTP " + r.Method + " " + info.route)
+			span.SetTag("http.route", info.route)
+		}
+		if v := VersionFrom(ctx); v != "" {
+			span.SetTag("api.version", v)
+		}
+		if info.userID != "" {
+			span.SetTag("user.id", info.userID)
+		}
+
+		status := rw.status
+		if status == 0 {
+			status = http.StatusOK
+		}
+		ext.HTTPStatusCode.Set(span, uint16(status))
+		if status >= http.StatusInternalServerError {
+			ext.Error.Set(span, true)
+		}
+	})
+}
@@ -0,0 +1,53 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go-skeleton-auth/internal/entity"
+
+	"github.com/gorilla/mux"
+	"github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/mocktracer"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTracingMiddleware(t *testing.T) {
+	tracer := mocktracer.New()
+	s := &Server{Tracer: tracer}
+
+	router := mux.NewRouter()
+	router.Use(routeMiddleware)
+	router.HandleFunc("/example/skeleton/{id}", func(w http.ResponseWriter, r *http.Request) {
+		require.NotNil(t, opentracing.SpanFromContext(r.Context()))
+		withClaims(r, entity.ContextValue{M: map[string]interface{}{"sub": "user-1"}})
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+	h := s.TracingMiddleware(router)
+
+	// the caller's trace is continued
+	parent := tracer.StartSpan("client")
+	r := httptest.NewRequest(http.MethodGet, "/example/skeleton/7", nil)
+	require.NoError(t, tracer.Inject(parent.Context(), opentracing.HTTPHeaders, opentracing.HTTPHeadersCarrier(r.Header)))
+	h.ServeHTTP(httptest.NewRecorder(), r)
+
+	// unmatched routes are traced too
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/nope", nil))
+
+	spans := tracer.FinishedSpans()
+	require.Len(t, spans, 2)
+
+	span := spans[0]
+	require.Equal(t, "HTTP GET /example/skeleton/{id}", span.OperationName)
+	require.Equal(t, parent.Context().(mocktracer.MockSpanContext).TraceID, span.SpanContext.TraceID)
+	require.Equal(t, "/example/skeleton/{id}", span.Tag("http.route"))
+	require.Equal(t, "user-1", span.Tag("user.id"))
+	require.Equal(t, uint16(http.StatusInternalServerError), span.Tag("http.status_code"))
+	require.Equal(t, true, span.Tag("error"))
+
+	span = spans[1]
+	require.Equal(t, "HTTP GET", span.OperationName)
+	require.Equal(t, uint16(http.StatusNotFound), span.Tag("http.status_code"))
+	require.Nil(t, span.Tag("error"))
+}
@@ -0,0 +1,164 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"go-skeleton-auth/internal/entity/errcode"
+	"go-skeleton-auth/pkg/errors"
+	"go-skeleton-auth/pkg/response"
+)
+
+const (
+	// versionHeader selects the version of unversioned paths
+	versionHeader = "Accept-Version"
+	// apiVersionHeader tells the version that served the request
+	apiVersionHeader = "API-Version"
+
+	defaultBasePath = "/example"
+)
+
+// versionSegment matches the version segment of a path, e.g. "/v2"
+var versionSegment = regexp.MustCompile(`^/(v[0-9]+)(/|$)`)
+
+// APIVersion ...
+type APIVersion struct {
+	// Name is the path segment of the version, e.g. "v1"
+	Name string
+	// Deprecated is when the version was deprecated, zero when it is not
+	Deprecated time.Time
+	// Sunset is when the version will be removed, zero when unknown
+	Sunset time.Time
+}
+
+// VersionOptions ...
+type VersionOptions struct {
+	// Versions are the served versions, oldest first, v1 only when empty
+	Versions []APIVersion
+	// Default serves unversioned paths requested without Accept-Version,
+	// the oldest version when empty so that existing clients keep working
+	Default string
+}
+
+func (o VersionOptions) versions() []APIVersion {
+	if len(o.Versions) == 0 {
+		return []APIVersion{{Name: "v1"}}
+	}
+	return o.Versions
+}
+
+// lookup returns the version named name and the one after it, if any
+func (o VersionOptions) lookup(name string) (v APIVersion, successor string, ok bool) {
+	versions := o.versions()
+	for i, v := range versions {
+		if v.Name == name {
+			if i+1 < len(versions) {
+				successor = versions[i+1].Name
+			}
+			return v, successor, true
+		}
+	}
+	return APIVersion{}, "", false
+}
+
+func (o VersionOptions) defaultVersion() string {
+	if o.Default != "" {
+		return o.Default
+	}
+	return o.versions()[0].Name
+}
+
+type versionKey struct{}
+
+// VersionFrom returns the API version of the request, for handlers whose
+// behaviour differs between versions
+func VersionFrom(ctx context.Context) string {
+	v, _ := ctx.Value(versionKey{}).(string)
+	return v
+}
+
+// OriginalURL returns the URL of r as the client requested it, before
+// VersionMiddleware removed the version segment, to build links to the API
+func OriginalURL(r *http.Request) *url.URL {
+	return response.OriginalURL(r)
+}
+
+// basePath is the prefix of the API routes
+func (s *Server) basePath() string {
+	if s.BasePath != "" {
+		return s.BasePath
+	}
+	return defaultBasePath
+}
+
+// VersionMiddleware resolves the API version of the requests under the base path:
+// the version segment of the path, e.g. /example/v2/skeleton, or Accept-Version, or
+// the default version. The version segment is removed from the path so that the
+// routes, and the path prefixes of the other middlewares, are the same for every
+// version; handlers get the version with VersionFrom and the requested URL with OriginalURL.
+//
+// Responses tell the version in API-Version, and the deprecation and sunset of
+// deprecated versions in Deprecation, Sunset and a successor-version Link.
+func (s *Server) VersionMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		base := s.basePath()
+		rest := strings.TrimPrefix(r.URL.Path, base)
+		if len(rest) == len(r.URL.Path) || rest != "" && rest[0] != '/' {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		var name string
+		if m := versionSegment.FindStringSubmatch(rest); m != nil {
+			if _, _, ok := s.Versions.lookup(m[1]); !ok {
+				// an unknown version is not found
+				next.ServeHTTP(w, r)
+				return
+			}
+			name = m[1]
+			rest = strings.TrimPrefix(rest, "/"+name)
+		} else {
+			w.Header().Add("Vary", versionHeader)
+			name = r.Header.Get(versionHeader)
+			if name == "" {
+				name = s.Versions.defaultVersion()
+			}
+		}
+
+		v, successor, ok := s.Versions.lookup(name)
+		if !ok {
+			resp := ParseErrorCode(errors.NewCode(errcode.UnsupportedVersion, strconv.Quote(name)))
+			resp.Render(w, r)
+			return
+		}
+
+		h := w.Header()
+		h.Set(apiVersionHeader, v.Name)
+		if !v.Deprecated.IsZero() {
+			h.Set("Deprecation", "@"+strconv.FormatInt(v.Deprecated.Unix(), 10))
+			if successor != "" {
+				h.Add("Link", "<"+base+"/"+successor+rest+`>; rel="successor-version"`)
+			}
+		}
+		if !v.Sunset.IsZero() {
+			h.Set("Sunset", v.Sunset.UTC().Format(http.TimeFormat))
+		}
+
+		ctx := context.WithValue(r.Context(), versionKey{}, v.Name)
+		if path := base + rest; path != r.URL.Path {
+			r = r.WithContext(response.WithOriginalURL(ctx, r.URL))
+			u := *r.URL
+			u.Path, u.RawPath = path, ""
+			r.URL = &u
+			r.RequestURI = u.RequestURI()
+		} else {
+			r = r.WithContext(ctx)
+		}
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,105 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"go-skeleton-auth/internal/entity/errcode"
+	"go-skeleton-auth/pkg/response"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestVersionMiddleware(t *testing.T) {
+	s := &Server{Versions: VersionOptions{
+		Versions: []APIVersion{
+			{
+				Name:       "v1",
+				Deprecated: time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC),
+				Sunset:     time.Date(2027, 4, 1, 0, 0, 0, 0, time.UTC),
+			},
+			{Name: "v2"},
+		},
+	}}
+
+	var path, version, original string
+	h := s.VersionMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path, version, original = r.URL.Path, VersionFrom(r.Context()), OriginalURL(r).RequestURI()
+	}))
+
+	testCases := []struct {
+		name          string
+		target        string
+		acceptVersion string
+		path          string
+		version       string
+		deprecated    bool
+	}{
+		{name: "path version", target: "/example/v2/skeleton?page=2", path: "/example/skeleton", version: "v2"},
+		{name: "deprecated path version", target: "/example/v1/skeleton", path: "/example/skeleton", version: "v1", deprecated: true},
+		{name: "default version", target: "/example/skeleton", path: "/example/skeleton", version: "v1", deprecated: true},
+		{name: "header version", target: "/example/skeleton", acceptVersion: "v2", path: "/example/skeleton", version: "v2"},
+		{name: "unknown path version", target: "/example/v3/skeleton", path: "/example/v3/skeleton"},
+		{name: "outside base path", target: "/examples/v2", path: "/examples/v2"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			path, version, original = "", "", ""
+			r := httptest.NewRequest(http.MethodGet, tc.target, nil)
+			if tc.acceptVersion != "" {
+				r.Header.Set("Accept-Version", tc.acceptVersion)
+			}
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+
+			require.Equal(t, tc.path, path)
+			require.Equal(t, tc.version, version)
+			// links are built from the requested URL, version segment included
+			require.Equal(t, tc.target, original)
+			require.Equal(t, tc.version, w.Header().Get("API-Version"))
+			if !tc.deprecated {
+				require.Empty(t, w.Header().Get("Deprecation"))
+				return
+			}
+			require.Equal(t, "@1790812800", w.Header().Get("Deprecation"))
+			require.Equal(t, "Thu, 01 Apr 2027 00:00:00 GMT", w.Header().Get("Sunset"))
+			require.Equal(t, `</example/v2/skeleton>; rel="successor-version"`, w.Header().Get("Link"))
+		})
+	}
+
+	r := httptest.NewRequest(http.MethodGet, "/example/skeleton", nil)
+	r.Header.Set("Accept-Version", "v9")
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	require.Equal(t, http.StatusBadRequest, w.Code)
+	var body response.Response
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&body))
+	require.Equal(t, int(errcode.UnsupportedVersion), body.Error.Code)
+}
+
+func TestVersionDoc(t *testing.T) {
+	doc := []byte(`{
+		"basePath": "/example",
+		"info": {"version": "1.0"},
+		"paths": {"/skeleton": {}, "/v1/legacy": {}, "/v2/report": {}}
+	}`)
+
+	b, err := versionDoc(doc, "v2")
+	require.NoError(t, err)
+
+	var spec struct {
+		BasePath string                     `json:"basePath"`
+		Info     map[string]string          `json:"info"`
+		Paths    map[string]json.RawMessage `json:"paths"`
+	}
+	require.NoError(t, json.Unmarshal(b, &spec))
+	require.Equal(t, "/example/v2", spec.BasePath)
+	require.Equal(t, "v2", spec.Info["version"])
+	require.Len(t, spec.Paths, 2)
+	require.Contains(t, spec.Paths, "/skeleton")
+	require.Contains(t, spec.Paths, "/report")
+}
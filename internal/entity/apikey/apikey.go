@@ -0,0 +1,61 @@
+package apikey
+
+import (
+	"database/sql"
+	"strings"
+	"time"
+)
+
+// APIKey model, only the SHA-256 hash of the key is stored
+type APIKey struct {
+	ID      int64  `db:"id" json:"id"`
+	KeyHash string `db:"key_hash" json:"-"`
+	// Prefix is the first characters of the key to help identifying it
+	Prefix string `db:"prefix" json:"prefix"`
+	Owner  string `db:"owner" json:"owner"`
+	// Scopes is a comma separated list of permissions, see ScopeList
+	Scopes     string       `db:"scopes" json:"scopes"`
+	CreatedAt  time.Time    `db:"created_at" json:"created_at"`
+	ExpiresAt  sql.NullTime `db:"expires_at" json:"-"`
+	LastUsedAt sql.NullTime `db:"last_used_at" json:"-"`
+}
+
+// ScopeList returns the scopes of the key
+func (k APIKey) ScopeList() []string {
+	var scopes []string
+	for _, s := range strings.Split(k.Scopes, ",") {
+		if s = strings.TrimSpace(s); s != "" {
+			scopes = append(scopes, s)
+		}
+	}
+	return scopes
+}
+
+// Expired reports whether the key can no longer be used at t
+func (k APIKey) Expired(t time.Time) bool {
+	return k.ExpiresAt.Valid && !t.Before(k.ExpiresAt.Time)
+}
+
+// Rotated is the result of a key rotation, Key is only available once
+type Rotated struct {
+	Key       string    `json:"key"`
+	APIKey    APIKey    `json:"api_key"`
+	OldExpiry time.Time `json:"old_key_expires_at"`
+}
+
+// CreateRequest is the body of the create API key endpoint
+type CreateRequest struct {
+	Owner  string   `json:"owner" validate:"required,max=128"`
+	Scopes []string `json:"scopes" validate:"required,max=32"`
+}
+
+// Created is the result of a key creation, Key is only available once
+type Created struct {
+	Key    string `json:"key"`
+	APIKey APIKey `json:"api_key"`
+}
+
+// RotateRequest selects the key to rotate
+type RotateRequest struct {
+	ID int64 `path:"id" validate:"required,min=1"`
+}
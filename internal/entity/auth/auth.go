@@ -18,9 +18,31 @@ type Err struct {
 	Code   int    `json:"code"`
 }
 
-// Auth ...
+// CheckRightsRequest is the request body of /checkrights
+type CheckRightsRequest struct {
+	Code string `json:"code"`
+}
+
+// CheckRightsBatchRequest is the request body of /checkrights/batch
+type CheckRightsBatchRequest struct {
+	Codes []string `json:"codes"`
+}
+
+// Auth is the response body of /checkrights
 type Auth struct {
-	Data     interface{} `json:"data"`
-	Metadata Metadata    `json:"metadata"`
-	Error    Err         `json:"error"`
+	Data     UserID   `json:"data"`
+	Metadata Metadata `json:"metadata"`
+	Error    Err      `json:"error"`
+}
+
+// Allowed reports whether the auth API granted the checked right
+func (a Auth) Allowed() bool {
+	return !a.Error.Status && a.Error.Code == 0
+}
+
+// BatchAuth is the response body of /checkrights/batch, Data is keyed by right code
+type BatchAuth struct {
+	Data     map[string]Auth `json:"data"`
+	Metadata Metadata        `json:"metadata"`
+	Error    Err             `json:"error"`
 }
@@ -1,5 +1,7 @@
 package entity
 
+import "context"
+
 // ContextKey ...
 type ContextKey string
 
@@ -11,3 +13,21 @@ type ContextValue struct {
 func (c ContextValue) Get(key string) interface{} {
 	return c.M[key]
 }
+
+// HasPermission reports whether the claims stored in ctx grant one of permissions.
+// Claims without permissions, e.g. from a client certificate, grant none.
+func HasPermission(ctx context.Context, permissions ...string) bool {
+	claims, _ := ctx.Value(ContextKey("claims")).(ContextValue)
+	actions, _ := claims.Get("permissions").(map[string]interface{})
+	for _, action := range actions {
+		granted, _ := action.([]interface{})
+		for _, g := range granted {
+			for _, permission := range permissions {
+				if p, ok := g.(string); ok && p == permission {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
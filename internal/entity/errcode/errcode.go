@@ -0,0 +1,214 @@
+// Package errcode registers the application error codes of the service.
+// Return them with errors.WithCode or errors.NewCode from pkg/errors.
+package errcode
+
+import (
+	"net/http"
+
+	"go-skeleton-auth/pkg/errors"
+)
+
+// Generic errors
+var (
+	BadRequest = errors.Register(errors.CodeInfo{
+		Code:       400,
+		HTTPStatus: http.StatusBadRequest,
+		GRPCCode:   errors.GRPCInvalidArgument,
+		Message:    "Bad request",
+		Level:      errors.LevelInfo,
+	})
+	UnsupportedVersion = errors.Register(errors.CodeInfo{
+		Code:       40002,
+		HTTPStatus: http.StatusBadRequest,
+		GRPCCode:   errors.GRPCInvalidArgument,
+		Message:    "Unsupported API version",
+		Level:      errors.LevelInfo,
+	})
+	Unauthorized = errors.Register(errors.CodeInfo{
+		Code:       401,
+		HTTPStatus: http.StatusUnauthorized,
+		GRPCCode:   errors.GRPCUnauthenticated,
+		Message:    "Unauthorized",
+		Level:      errors.LevelInfo,
+	})
+	NotFound = errors.Register(errors.CodeInfo{
+		Code:       404,
+		HTTPStatus: http.StatusNotFound,
+		GRPCCode:   errors.GRPCNotFound,
+		Message:    "404 Not Found",
+		Level:      errors.LevelInfo,
+	})
+	PayloadTooLarge = errors.Register(errors.CodeInfo{
+		Code:       413,
+		HTTPStatus: http.StatusRequestEntityTooLarge,
+		GRPCCode:   errors.GRPCResourceExhausted,
+		Message:    "Request body too large",
+		Level:      errors.LevelInfo,
+	})
+	UnsupportedEncoding = errors.Register(errors.CodeInfo{
+		Code:       415,
+		HTTPStatus: http.StatusUnsupportedMediaType,
+		GRPCCode:   errors.GRPCInvalidArgument,
+		Message:    "Unsupported Content-Encoding, use gzip",
+		Level:      errors.LevelInfo,
+	})
+	ValidationFailed = errors.Register(errors.CodeInfo{
+		Code:       422,
+		HTTPStatus: http.StatusUnprocessableEntity,
+		GRPCCode:   errors.GRPCInvalidArgument,
+		Message:    "Validation failed",
+		Level:      errors.LevelInfo,
+	})
+	RateLimited = errors.Register(errors.CodeInfo{
+		Code:       429,
+		HTTPStatus: http.StatusTooManyRequests,
+		GRPCCode:   errors.GRPCResourceExhausted,
+		Message:    "Too many requests",
+		Level:      errors.LevelInfo,
+	})
+	Internal = errors.Register(errors.CodeInfo{
+		Code:       500,
+		HTTPStatus: http.StatusInternalServerError,
+		GRPCCode:   errors.GRPCInternal,
+		Message:    "Internal server error",
+		Level:      errors.LevelError,
+	})
+	Panic = errors.Register(errors.CodeInfo{
+		Code:       50001,
+		HTTPStatus: http.StatusInternalServerError,
+		GRPCCode:   errors.GRPCInternal,
+		Message:    "Internal server error",
+		Level:      errors.LevelError,
+	})
+	RequestCanceled = errors.Register(errors.CodeInfo{
+		Code:       50303,
+		HTTPStatus: http.StatusServiceUnavailable,
+		GRPCCode:   errors.GRPCCanceled,
+		Message:    "Request canceled",
+		Level:      errors.LevelInfo,
+	})
+	NotReady = errors.Register(errors.CodeInfo{
+		Code:       50304,
+		HTTPStatus: http.StatusServiceUnavailable,
+		GRPCCode:   errors.GRPCUnavailable,
+		Message:    "Service not ready",
+		Level:      errors.LevelError,
+	})
+	Timeout = errors.Register(errors.CodeInfo{
+		Code:       50401,
+		HTTPStatus: http.StatusGatewayTimeout,
+		GRPCCode:   errors.GRPCDeadlineExceeded,
+		Message:    "Request timed out",
+		Level:      errors.LevelError,
+	})
+)
+
+// Data errors
+var (
+	FetchFailed = errors.Register(errors.CodeInfo{
+		Code:       10001,
+		HTTPStatus: http.StatusInternalServerError,
+		GRPCCode:   errors.GRPCInternal,
+		Message:    "Failed to fetch data",
+		Level:      errors.LevelError,
+	})
+	InsertFailed = errors.Register(errors.CodeInfo{
+		Code:       10002,
+		HTTPStatus: http.StatusInternalServerError,
+		GRPCCode:   errors.GRPCInternal,
+		Message:    "Failed to insert data",
+		Level:      errors.LevelError,
+	})
+)
+
+// Idempotency errors
+var (
+	InvalidIdempotencyKey = errors.Register(errors.CodeInfo{
+		Code:       40001,
+		HTTPStatus: http.StatusBadRequest,
+		GRPCCode:   errors.GRPCInvalidArgument,
+		Message:    "Invalid Idempotency-Key",
+		Level:      errors.LevelInfo,
+	})
+	IdempotencyInProgress = errors.Register(errors.CodeInfo{
+		Code:       40901,
+		HTTPStatus: http.StatusConflict,
+		GRPCCode:   errors.GRPCAborted,
+		Message:    "A request with this Idempotency-Key is in progress",
+		Level:      errors.LevelInfo,
+	})
+	IdempotencyKeyReused = errors.Register(errors.CodeInfo{
+		Code:       42201,
+		HTTPStatus: http.StatusUnprocessableEntity,
+		GRPCCode:   errors.GRPCFailedPrecondition,
+		Message:    "Idempotency-Key reused with a different request",
+		Level:      errors.LevelInfo,
+	})
+)
+
+// Authentication and authorization errors
+var (
+	MissingCredentials = errors.Register(errors.CodeInfo{
+		Code:       40101,
+		HTTPStatus: http.StatusUnauthorized,
+		GRPCCode:   errors.GRPCUnauthenticated,
+		Message:    "Missing credentials",
+		Level:      errors.LevelInfo,
+	})
+	UnsupportedScheme = errors.Register(errors.CodeInfo{
+		Code:       40102,
+		HTTPStatus: http.StatusUnauthorized,
+		GRPCCode:   errors.GRPCUnauthenticated,
+		Message:    "Invalid token: unsupported token type",
+		Level:      errors.LevelInfo,
+	})
+	InvalidToken = errors.Register(errors.CodeInfo{
+		Code:       40103,
+		HTTPStatus: http.StatusUnauthorized,
+		GRPCCode:   errors.GRPCUnauthenticated,
+		Message:    "Invalid token",
+		Level:      errors.LevelInfo,
+	})
+	ExpiredToken = errors.Register(errors.CodeInfo{
+		Code:       40104,
+		HTTPStatus: http.StatusUnauthorized,
+		GRPCCode:   errors.GRPCUnauthenticated,
+		Message:    "Invalid token: token is expired",
+		Level:      errors.LevelInfo,
+	})
+	RevokedToken = errors.Register(errors.CodeInfo{
+		Code:       40105,
+		HTTPStatus: http.StatusUnauthorized,
+		GRPCCode:   errors.GRPCUnauthenticated,
+		Message:    "Invalid token: token has been revoked",
+		Level:      errors.LevelInfo,
+	})
+	InvalidAPIKey = errors.Register(errors.CodeInfo{
+		Code:       40106,
+		HTTPStatus: http.StatusUnauthorized,
+		GRPCCode:   errors.GRPCUnauthenticated,
+		Message:    "Invalid API key",
+		Level:      errors.LevelInfo,
+	})
+	Forbidden = errors.Register(errors.CodeInfo{
+		Code:       40301,
+		HTTPStatus: http.StatusForbidden,
+		GRPCCode:   errors.GRPCPermissionDenied,
+		Message:    "Forbidden",
+		Level:      errors.LevelInfo,
+	})
+	RevocationCheckDown = errors.Register(errors.CodeInfo{
+		Code:       50301,
+		HTTPStatus: http.StatusServiceUnavailable,
+		GRPCCode:   errors.GRPCUnavailable,
+		Message:    "Token revocation check unavailable",
+		Level:      errors.LevelError,
+	})
+	AuthUnavailable = errors.Register(errors.CodeInfo{
+		Code:       50302,
+		HTTPStatus: http.StatusServiceUnavailable,
+		GRPCCode:   errors.GRPCUnavailable,
+		Message:    "Auth API unavailable",
+		Level:      errors.LevelError,
+	})
+)
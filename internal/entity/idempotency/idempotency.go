@@ -0,0 +1,19 @@
+package idempotency
+
+import "time"
+
+// Record is a request made with an Idempotency-Key and, once completed, its response
+type Record struct {
+	// Key is the hash of the principal and the Idempotency-Key
+	Key string `db:"idempotency_key" json:"key"`
+	// Fingerprint is the hash of the method, URL and body of the request
+	Fingerprint string `db:"fingerprint" json:"fingerprint"`
+	// Completed is false while the first request is in flight
+	Completed   bool      `db:"completed" json:"completed"`
+	StatusCode  int       `db:"status_code" json:"status_code"`
+	ContentType string    `db:"content_type" json:"content_type"`
+	Body        []byte    `db:"body" json:"body"`
+	CreatedAt   time.Time `db:"created_at" json:"created_at"`
+	// ExpiresAt is when the key can be reused
+	ExpiresAt time.Time `db:"expires_at" json:"expires_at"`
+}
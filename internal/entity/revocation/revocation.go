@@ -0,0 +1,23 @@
+package revocation
+
+import "time"
+
+// Revocation revokes either a single token by its jti, or every token of
+// Subject issued at or before IssuedBefore
+type Revocation struct {
+	ID           int64     `db:"id" json:"id"`
+	JTI          string    `db:"jti" json:"jti,omitempty"`
+	Subject      string    `db:"subject" json:"subject,omitempty"`
+	IssuedBefore time.Time `db:"issued_before" json:"issued_before,omitempty"`
+	// ExpiresAt is when the revoked tokens expire anyway and the revocation can be dropped
+	ExpiresAt time.Time `db:"expires_at" json:"expires_at"`
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
+}
+
+// RevokeRequest is the body of the revoke token endpoint
+type RevokeRequest struct {
+	JTI          string     `json:"jti" validate:"max=128"`
+	Subject      string     `json:"subject" validate:"max=128"`
+	IssuedBefore *time.Time `json:"issued_before"`
+	ExpiresAt    *time.Time `json:"expires_at"`
+}
@@ -1,7 +1,10 @@
 package skeleton
 
+import "time"
+
 // Skeleton model
 type Skeleton struct {
-	SkeletonID   int `db:"skeleton_id" json:"skeleton_id"`
-	SkeletonName int `db:"skeleton_name" json:"skeleton_name"`
+	SkeletonID   int       `db:"skeleton_id" json:"skeleton_id"`
+	SkeletonName string    `db:"skeleton_name" json:"skeleton_name"`
+	CreatedAt    time.Time `db:"created_at" json:"created_at"`
 }
@@ -0,0 +1,60 @@
+package apikey
+
+import (
+	"context"
+	"time"
+
+	"go-skeleton-auth/internal/entity/apikey"
+	jaegerLog "go-skeleton-auth/pkg/log"
+
+	"github.com/opentracing/opentracing-go"
+)
+
+const (
+	// keyPrefixLen is the number of characters of a key stored in clear to identify it
+	keyPrefixLen = 8
+	// lastUsedInterval throttles last used updates to one write per key per interval
+	lastUsedInterval = time.Minute
+
+	// permissionManage is required to create and rotate keys
+	permissionManage = "apikey:manage"
+)
+
+// Data ...
+type Data interface {
+	GetAPIKeyByHash(ctx context.Context, keyHash string) (apikey.APIKey, error)
+	GetAPIKeyByID(ctx context.Context, id int64) (apikey.APIKey, error)
+	InsertAPIKey(ctx context.Context, key apikey.APIKey) (int64, error)
+	UpdateAPIKeyExpiry(ctx context.Context, id int64, expiresAt time.Time) error
+	UpdateAPIKeyLastUsed(ctx context.Context, id int64, lastUsedAt time.Time) error
+}
+
+// Service ...
+type Service struct {
+	data Data
+	// overlap is how long a rotated key stays valid next to its replacement
+	overlap time.Duration
+	tracer  opentracing.Tracer
+	logger  jaegerLog.Factory
+}
+
+// New ...
+func New(data Data, overlap time.Duration, tracer opentracing.Tracer, logger jaegerLog.Factory) Service {
+	return Service{
+		data:    data,
+		overlap: overlap,
+		tracer:  tracer,
+		logger:  logger,
+	}
+}
+
+// startSpan starts a child span when ctx carries a span
+func (s Service) startSpan(ctx context.Context, operation string) (context.Context, func()) {
+	span := opentracing.SpanFromContext(ctx)
+	if span == nil {
+		return ctx, func() {}
+	}
+
+	span = s.tracer.StartSpan(operation, opentracing.ChildOf(span.Context()))
+	return opentracing.ContextWithSpan(ctx, span), span.Finish
+}
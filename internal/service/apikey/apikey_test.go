@@ -0,0 +1,127 @@
+package apikey
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"go-skeleton-auth/internal/entity"
+	"go-skeleton-auth/internal/entity/apikey"
+	"go-skeleton-auth/internal/entity/errcode"
+	"go-skeleton-auth/pkg/errors"
+	jaegerLog "go-skeleton-auth/pkg/log"
+
+	"github.com/opentracing/opentracing-go"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type fakeData struct {
+	keys     map[int64]apikey.APIKey
+	lastUsed int
+	err      error
+}
+
+func (f *fakeData) GetAPIKeyByHash(ctx context.Context, keyHash string) (apikey.APIKey, error) {
+	if f.err != nil {
+		return apikey.APIKey{}, f.err
+	}
+	for _, k := range f.keys {
+		if k.KeyHash == keyHash {
+			return k, nil
+		}
+	}
+	return apikey.APIKey{}, sql.ErrNoRows
+}
+
+func (f *fakeData) GetAPIKeyByID(ctx context.Context, id int64) (apikey.APIKey, error) {
+	k, ok := f.keys[id]
+	if !ok {
+		return apikey.APIKey{}, sql.ErrNoRows
+	}
+	return k, nil
+}
+
+func (f *fakeData) InsertAPIKey(ctx context.Context, key apikey.APIKey) (int64, error) {
+	key.ID = int64(len(f.keys) + 1)
+	f.keys[key.ID] = key
+	return key.ID, nil
+}
+
+func (f *fakeData) UpdateAPIKeyExpiry(ctx context.Context, id int64, expiresAt time.Time) error {
+	k := f.keys[id]
+	k.ExpiresAt = sql.NullTime{Time: expiresAt, Valid: true}
+	f.keys[id] = k
+	return nil
+}
+
+func (f *fakeData) UpdateAPIKeyLastUsed(ctx context.Context, id int64, lastUsedAt time.Time) error {
+	k := f.keys[id]
+	k.LastUsedAt = sql.NullTime{Time: lastUsedAt, Valid: true}
+	f.keys[id] = k
+	f.lastUsed++
+	return nil
+}
+
+func TestAuthenticateAndRotate(t *testing.T) {
+	data := &fakeData{keys: map[int64]apikey.APIKey{}}
+	s := New(data, time.Hour, opentracing.NoopTracer{}, jaegerLog.NewFactory(zap.NewNop()))
+	ctx := context.WithValue(context.Background(), entity.ContextKey("claims"), entity.ContextValue{M: map[string]interface{}{
+		"permissions": map[string]interface{}{"apikey": []interface{}{permissionManage}},
+	}})
+
+	_, _, err := s.CreateAPIKey(context.Background(), "billing", nil)
+	code, _ := errors.CodeOf(err)
+	require.Equal(t, errcode.Forbidden, code)
+
+	key, k, err := s.CreateAPIKey(ctx, "billing", []string{"skeleton:read", "skeleton:write"})
+	require.NoError(t, err)
+	require.Equal(t, key[:keyPrefixLen], k.Prefix)
+	require.NotEqual(t, key, k.KeyHash)
+
+	got, err := s.Authenticate(ctx, key)
+	require.NoError(t, err)
+	require.Equal(t, []string{"skeleton:read", "skeleton:write"}, got.ScopeList())
+
+	// last used is only written once per interval
+	_, err = s.Authenticate(ctx, key)
+	require.NoError(t, err)
+	require.Equal(t, 1, data.lastUsed)
+
+	_, err = s.Authenticate(ctx, "unknown")
+	code, _ = errors.CodeOf(err)
+	require.Equal(t, errcode.InvalidAPIKey, code)
+
+	_, err = s.RotateAPIKey(ctx, 42)
+	code, _ = errors.CodeOf(err)
+	require.Equal(t, errcode.NotFound, code)
+
+	rotated, err := s.RotateAPIKey(ctx, k.ID)
+	require.NoError(t, err)
+	require.WithinDuration(t, time.Now().Add(time.Hour), rotated.OldExpiry, time.Minute)
+
+	// both keys work during the overlap window
+	_, err = s.Authenticate(ctx, key)
+	require.NoError(t, err)
+	_, err = s.Authenticate(ctx, rotated.Key)
+	require.NoError(t, err)
+
+	// the old key stops working once the window is over
+	old := data.keys[k.ID]
+	old.ExpiresAt.Time = time.Now().Add(-time.Second)
+	data.keys[k.ID] = old
+	_, err = s.Authenticate(ctx, key)
+	require.Error(t, err)
+}
+
+func TestAuthenticateLookupFailure(t *testing.T) {
+	data := &fakeData{keys: map[int64]apikey.APIKey{}, err: errors.New("db down")}
+	s := New(data, time.Hour, opentracing.NoopTracer{}, jaegerLog.NewFactory(zap.NewNop()))
+
+	// a failing store is not an invalid key
+	_, err := s.Authenticate(context.Background(), "any")
+	require.Error(t, err)
+	_, ok := errors.CodeOf(err)
+	require.False(t, ok)
+}
@@ -0,0 +1,48 @@
+package apikey
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"time"
+
+	"go-skeleton-auth/internal/entity/apikey"
+	"go-skeleton-auth/internal/entity/errcode"
+	"go-skeleton-auth/pkg/errors"
+
+	"go.uber.org/zap"
+)
+
+// Authenticate returns the API key matching key
+func (s Service) Authenticate(ctx context.Context, key string) (apikey.APIKey, error) {
+	ctx, finish := s.startSpan(ctx, "AuthenticateAPIKey")
+	defer finish()
+
+	k, err := s.data.GetAPIKeyByHash(ctx, hashKey(key))
+	if errors.Is(err, sql.ErrNoRows) {
+		return apikey.APIKey{}, errors.NewCode(errcode.InvalidAPIKey, "invalid API key")
+	}
+	if err != nil {
+		return apikey.APIKey{}, errors.Wrap(err, "[SERVICE][Authenticate]")
+	}
+
+	now := time.Now()
+	if k.Expired(now) {
+		return apikey.APIKey{}, errors.NewCode(errcode.InvalidAPIKey, "invalid API key")
+	}
+
+	if !k.LastUsedAt.Valid || now.Sub(k.LastUsedAt.Time) >= lastUsedInterval {
+		// last used tracking must not fail the request
+		if err = s.data.UpdateAPIKeyLastUsed(ctx, k.ID, now); err != nil {
+			s.logger.For(ctx).Error("API key last used update failed", zap.Int64("id", k.ID), zap.Error(err))
+		}
+	}
+
+	return k, nil
+}
+
+func hashKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,94 @@
+package apikey
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"strings"
+	"time"
+
+	"go-skeleton-auth/internal/entity"
+	"go-skeleton-auth/internal/entity/apikey"
+	"go-skeleton-auth/internal/entity/errcode"
+	"go-skeleton-auth/pkg/errors"
+)
+
+// CreateAPIKey creates a key for owner, the returned key is not stored and cannot be recovered
+func (s Service) CreateAPIKey(ctx context.Context, owner string, scopes []string) (string, apikey.APIKey, error) {
+	ctx, finish := s.startSpan(ctx, "CreateAPIKey")
+	defer finish()
+
+	if !entity.HasPermission(ctx, permissionManage) {
+		return "", apikey.APIKey{}, errors.NewCode(errcode.Forbidden, "forbidden")
+	}
+
+	key, err := generateKey()
+	if err != nil {
+		return "", apikey.APIKey{}, errors.Wrap(err, "[SERVICE][CreateAPIKey]")
+	}
+
+	k := apikey.APIKey{
+		KeyHash:   hashKey(key),
+		Prefix:    key[:keyPrefixLen],
+		Owner:     owner,
+		Scopes:    strings.Join(scopes, ","),
+		CreatedAt: time.Now(),
+	}
+	if k.ID, err = s.data.InsertAPIKey(ctx, k); err != nil {
+		return "", apikey.APIKey{}, errors.Wrap(err, "[SERVICE][CreateAPIKey]")
+	}
+	return key, k, nil
+}
+
+// RotateAPIKey replaces key id by a new key with the same owner and scopes.
+// The old key keeps working for the configured overlap window.
+func (s Service) RotateAPIKey(ctx context.Context, id int64) (apikey.Rotated, error) {
+	ctx, finish := s.startSpan(ctx, "RotateAPIKey")
+	defer finish()
+
+	if !entity.HasPermission(ctx, permissionManage) {
+		return apikey.Rotated{}, errors.NewCode(errcode.Forbidden, "forbidden")
+	}
+
+	old, err := s.data.GetAPIKeyByID(ctx, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return apikey.Rotated{}, errors.WithCode(errors.Wrap(err, "[SERVICE][RotateAPIKey]"), errcode.NotFound)
+	}
+	if err != nil {
+		return apikey.Rotated{}, errors.Wrap(err, "[SERVICE][RotateAPIKey]")
+	}
+
+	now := time.Now()
+	if old.Expired(now) {
+		return apikey.Rotated{}, errors.NewCode(errcode.BadRequest, "[SERVICE][RotateAPIKey] key already expired")
+	}
+
+	key, k, err := s.CreateAPIKey(ctx, old.Owner, old.ScopeList())
+	if err != nil {
+		return apikey.Rotated{}, errors.Wrap(err, "[SERVICE][RotateAPIKey]")
+	}
+
+	// never extend the life of a key that already expires within the window
+	oldExpiry := now.Add(s.overlap)
+	if old.ExpiresAt.Valid && old.ExpiresAt.Time.Before(oldExpiry) {
+		oldExpiry = old.ExpiresAt.Time
+	}
+	if err = s.data.UpdateAPIKeyExpiry(ctx, old.ID, oldExpiry); err != nil {
+		return apikey.Rotated{}, errors.Wrap(err, "[SERVICE][RotateAPIKey]")
+	}
+
+	return apikey.Rotated{
+		Key:       key,
+		APIKey:    k,
+		OldExpiry: oldExpiry,
+	}, nil
+}
+
+func generateKey() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
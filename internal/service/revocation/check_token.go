@@ -0,0 +1,118 @@
+package revocation
+
+import (
+	"context"
+	"time"
+
+	"go-skeleton-auth/internal/entity/revocation"
+	"go-skeleton-auth/pkg/errors"
+
+	"go.uber.org/zap"
+)
+
+// IsRevoked reports whether the token with the given jti, subject and issued at time is revoked.
+// An error is only returned when the revocations were never loaded.
+func (s Service) IsRevoked(ctx context.Context, jti, subject string, issuedAt time.Time) (bool, error) {
+	if err := s.sync(ctx); err != nil {
+		return false, err
+	}
+
+	s.index.mu.RLock()
+	defer s.index.mu.RUnlock()
+
+	now := time.Now()
+	if jti != "" {
+		if expiresAt, ok := s.index.jtis[jti]; ok && expiresAt.After(now) {
+			return true, nil
+		}
+	}
+	if subject != "" {
+		rev, ok := s.index.subjects[subject]
+		if ok && rev.ExpiresAt.After(now) && !issuedAt.After(rev.IssuedBefore) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// sync loads revocations created since the last sync when the index is stale.
+// Only one caller syncs at a time, the others keep using the current index or,
+// before the first load, wait for it and fail closed when it does not succeed.
+// Ids are allocated before the rows commit, so an incremental sync misses a row
+// that commits after a higher id was loaded: the index is rebuilt from every
+// unexpired revocation once per full sync interval to pick those up.
+func (s Service) sync(ctx context.Context) error {
+	idx := s.index
+
+	idx.mu.Lock()
+	if time.Since(idx.syncedAt) < s.syncInterval {
+		idx.mu.Unlock()
+		return nil
+	}
+	if done := idx.syncing; done != nil {
+		never := idx.syncedAt.IsZero()
+		idx.mu.Unlock()
+		if !never {
+			return nil
+		}
+		return s.waitFirstSync(ctx, done)
+	}
+	never := idx.syncedAt.IsZero()
+	full := time.Since(idx.fullSyncedAt) >= s.fullSyncInterval
+	lastID := idx.lastID
+	if full {
+		lastID = 0
+	}
+	done := make(chan struct{})
+	idx.syncing = done
+	idx.mu.Unlock()
+
+	revocations, err := s.data.GetRevocationsAfter(ctx, lastID)
+
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	defer close(done)
+	idx.syncing = nil
+
+	if err != nil {
+		s.logger.For(ctx).Error("Token revocation sync failed", zap.Error(err))
+		if never {
+			return errors.Wrap(err, "[SERVICE][IsRevoked]")
+		}
+		// keep serving the last known revocations
+		return nil
+	}
+
+	now := time.Now()
+	if full {
+		idx.jtis = make(map[string]time.Time)
+		idx.subjects = make(map[string]revocation.Revocation)
+		idx.fullSyncedAt = now
+	}
+	for _, rev := range revocations {
+		idx.add(rev)
+		if rev.ID > idx.lastID {
+			idx.lastID = rev.ID
+		}
+	}
+	idx.prune(now)
+	idx.syncedAt = now
+	return nil
+}
+
+// waitFirstSync waits for the sync in progress when nothing was loaded yet,
+// there is no index to check tokens against until it succeeds
+func (s Service) waitFirstSync(ctx context.Context, done <-chan struct{}) error {
+	select {
+	case <-done:
+	case <-ctx.Done():
+		return errors.Wrap(ctx.Err(), "[SERVICE][IsRevoked]")
+	}
+
+	s.index.mu.RLock()
+	defer s.index.mu.RUnlock()
+	if s.index.syncedAt.IsZero() {
+		return errors.Wrap(errNeverLoaded, "[SERVICE][IsRevoked]")
+	}
+	return nil
+}
@@ -0,0 +1,110 @@
+package revocation
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go-skeleton-auth/internal/entity/revocation"
+	"go-skeleton-auth/pkg/errors"
+	jaegerLog "go-skeleton-auth/pkg/log"
+
+	"github.com/opentracing/opentracing-go"
+)
+
+const (
+	defaultSyncInterval     = 5 * time.Second
+	defaultMaxTokenLifetime = 24 * time.Hour
+	// fullSyncEvery is the number of sync intervals between two full reloads
+	fullSyncEvery = 12
+
+	// permissionRevoke is required to revoke tokens
+	permissionRevoke = "token:revoke"
+)
+
+// errNeverLoaded is returned while no sync has succeeded yet
+var errNeverLoaded = errors.New("revocations were never loaded")
+
+// Data is a revocation store, see the MySQL and in-memory stores in data/revocation
+type Data interface {
+	GetRevocationsAfter(ctx context.Context, id int64) ([]revocation.Revocation, error)
+	InsertRevocation(ctx context.Context, rev revocation.Revocation) (int64, error)
+}
+
+// Service checks tokens against an in-memory index of revocations that is
+// synced from the store at most once per sync interval, so checks do not
+// add a store round-trip per request
+type Service struct {
+	data Data
+	// syncInterval bounds how long a revocation made on another replica takes to apply
+	syncInterval time.Duration
+	// fullSyncInterval bounds how long a revocation missed by an incremental sync takes to apply
+	fullSyncInterval time.Duration
+	// maxTokenLifetime is how long a revocation is kept when the token expiry is unknown
+	maxTokenLifetime time.Duration
+	index            *index
+	tracer           opentracing.Tracer
+	logger           jaegerLog.Factory
+}
+
+type index struct {
+	mu       sync.RWMutex
+	jtis     map[string]time.Time
+	subjects map[string]revocation.Revocation
+	lastID   int64
+	syncedAt time.Time
+	// fullSyncedAt is the time of the last full reload
+	fullSyncedAt time.Time
+	// syncing is closed when the sync in progress ends, nil when there is none
+	syncing chan struct{}
+}
+
+// New ...
+func New(data Data, syncInterval, maxTokenLifetime time.Duration, tracer opentracing.Tracer, logger jaegerLog.Factory) Service {
+	if syncInterval <= 0 {
+		syncInterval = defaultSyncInterval
+	}
+	if maxTokenLifetime <= 0 {
+		maxTokenLifetime = defaultMaxTokenLifetime
+	}
+
+	return Service{
+		data:             data,
+		syncInterval:     syncInterval,
+		fullSyncInterval: fullSyncEvery * syncInterval,
+		maxTokenLifetime: maxTokenLifetime,
+		index: &index{
+			jtis:     make(map[string]time.Time),
+			subjects: make(map[string]revocation.Revocation),
+		},
+		tracer: tracer,
+		logger: logger,
+	}
+}
+
+// add indexes rev, it must be called with idx.mu held
+func (idx *index) add(rev revocation.Revocation) {
+	if rev.JTI != "" {
+		idx.jtis[rev.JTI] = rev.ExpiresAt
+	}
+	if rev.Subject != "" {
+		// the latest cut-off of a subject includes the earlier ones
+		if cur, ok := idx.subjects[rev.Subject]; !ok || rev.IssuedBefore.After(cur.IssuedBefore) {
+			idx.subjects[rev.Subject] = rev
+		}
+	}
+}
+
+// prune drops expired revocations, it must be called with idx.mu held
+func (idx *index) prune(now time.Time) {
+	for jti, expiresAt := range idx.jtis {
+		if !expiresAt.After(now) {
+			delete(idx.jtis, jti)
+		}
+	}
+	for sub, rev := range idx.subjects {
+		if !rev.ExpiresAt.After(now) {
+			delete(idx.subjects, sub)
+		}
+	}
+}
@@ -0,0 +1,160 @@
+package revocation
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	revocationData "go-skeleton-auth/internal/data/revocation"
+	"go-skeleton-auth/internal/entity"
+	"go-skeleton-auth/internal/entity/revocation"
+	jaegerLog "go-skeleton-auth/pkg/log"
+
+	"github.com/opentracing/opentracing-go"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func adminContext() context.Context {
+	return context.WithValue(context.Background(), entity.ContextKey("claims"), entity.ContextValue{
+		M: map[string]interface{}{
+			"permissions": map[string]interface{}{
+				"admin": []interface{}{permissionRevoke},
+			},
+		},
+	})
+}
+
+func TestRevokeToken(t *testing.T) {
+	store := revocationData.NewMemory()
+	s := New(store, time.Hour, time.Hour, opentracing.NoopTracer{}, jaegerLog.NewFactory(zap.NewNop()))
+	ctx := context.Background()
+	now := time.Now()
+
+	revoked, err := s.IsRevoked(ctx, "jti-1", "user-1", now)
+	require.NoError(t, err)
+	require.False(t, revoked)
+
+	_, err = s.RevokeToken(ctx, revocation.RevokeRequest{JTI: "jti-1"})
+	require.Error(t, err, "permission is required")
+
+	_, err = s.RevokeToken(adminContext(), revocation.RevokeRequest{JTI: "jti-1"})
+	require.NoError(t, err)
+	revoked, err = s.IsRevoked(ctx, "jti-1", "user-1", now)
+	require.NoError(t, err)
+	require.True(t, revoked)
+
+	_, err = s.RevokeToken(adminContext(), revocation.RevokeRequest{Subject: "user-2"})
+	require.NoError(t, err)
+	revoked, err = s.IsRevoked(ctx, "jti-2", "user-2", now.Add(-time.Minute))
+	require.NoError(t, err)
+	require.True(t, revoked)
+	// tokens issued after the cut-off stay valid
+	revoked, err = s.IsRevoked(ctx, "jti-3", "user-2", now.Add(time.Minute))
+	require.NoError(t, err)
+	require.False(t, revoked)
+}
+
+func TestRevocationSync(t *testing.T) {
+	store := revocationData.NewMemory()
+	logger := jaegerLog.NewFactory(zap.NewNop())
+	replicaA := New(store, time.Hour, time.Hour, opentracing.NoopTracer{}, logger)
+	replicaB := New(store, time.Millisecond, time.Hour, opentracing.NoopTracer{}, logger)
+	ctx := context.Background()
+
+	_, err := replicaB.IsRevoked(ctx, "jti-1", "", time.Now())
+	require.NoError(t, err)
+
+	_, err = replicaA.RevokeToken(adminContext(), revocation.RevokeRequest{JTI: "jti-1"})
+	require.NoError(t, err)
+
+	time.Sleep(5 * time.Millisecond)
+	revoked, err := replicaB.IsRevoked(ctx, "jti-1", "", time.Now())
+	require.NoError(t, err)
+	require.True(t, revoked)
+}
+
+// lateStore returns only the revocations marked committed, like a database
+// where a lower id commits after a higher one
+type lateStore struct {
+	mu        sync.Mutex
+	rows      []revocation.Revocation
+	committed map[int64]bool
+	// release blocks GetRevocationsAfter until it is closed, when set
+	release chan struct{}
+}
+
+func (l *lateStore) GetRevocationsAfter(ctx context.Context, id int64) ([]revocation.Revocation, error) {
+	if l.release != nil {
+		<-l.release
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	var res []revocation.Revocation
+	for _, rev := range l.rows {
+		if rev.ID > id && l.committed[rev.ID] {
+			res = append(res, rev)
+		}
+	}
+	return res, nil
+}
+
+func (l *lateStore) InsertRevocation(ctx context.Context, rev revocation.Revocation) (int64, error) {
+	return 0, nil
+}
+
+func TestRevocationSyncLateCommit(t *testing.T) {
+	expiresAt := time.Now().Add(time.Hour)
+	store := &lateStore{
+		rows: []revocation.Revocation{
+			{ID: 1, JTI: "jti-1", ExpiresAt: expiresAt},
+			{ID: 2, JTI: "jti-2", ExpiresAt: expiresAt},
+		},
+		committed: map[int64]bool{2: true},
+	}
+	s := New(store, time.Millisecond, time.Hour, opentracing.NoopTracer{}, jaegerLog.NewFactory(zap.NewNop()))
+	ctx := context.Background()
+
+	revoked, err := s.IsRevoked(ctx, "jti-1", "", time.Now())
+	require.NoError(t, err)
+	require.False(t, revoked)
+
+	// id 1 commits after id 2 was loaded, only a full reload sees it
+	store.mu.Lock()
+	store.committed[1] = true
+	store.mu.Unlock()
+
+	require.Eventually(t, func() bool {
+		revoked, err := s.IsRevoked(ctx, "jti-1", "", time.Now())
+		return err == nil && revoked
+	}, time.Second, time.Millisecond)
+}
+
+func TestRevocationFirstSyncFailsClosed(t *testing.T) {
+	store := &lateStore{release: make(chan struct{})}
+	s := New(store, time.Hour, time.Hour, opentracing.NoopTracer{}, jaegerLog.NewFactory(zap.NewNop()))
+
+	loaded := make(chan error)
+	go func() {
+		_, err := s.IsRevoked(context.Background(), "jti-1", "", time.Now())
+		loaded <- err
+	}()
+
+	// the first load is in progress, other callers must not check an empty index
+	require.Eventually(t, func() bool {
+		s.index.mu.RLock()
+		defer s.index.mu.RUnlock()
+		return s.index.syncing != nil
+	}, time.Second, time.Millisecond)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err := s.IsRevoked(ctx, "jti-1", "", time.Now())
+	require.Error(t, err)
+
+	close(store.release)
+	require.NoError(t, <-loaded)
+	_, err = s.IsRevoked(context.Background(), "jti-1", "", time.Now())
+	require.NoError(t, err)
+}
@@ -3,11 +3,16 @@ package skeleton
 import (
 	"context"
 
+	"go-skeleton-auth/internal/entity/skeleton"
+	"go-skeleton-auth/pkg/authz"
+	"go-skeleton-auth/pkg/errors"
+	"go-skeleton-auth/pkg/pagination"
+
 	"github.com/opentracing/opentracing-go"
 )
 
-// GetSkeleton ...
-func (s Service) GetSkeleton(ctx context.Context) error {
+// GetSkeleton returns the page of skeletons selected by q
+func (s Service) GetSkeleton(ctx context.Context, q pagination.Query) ([]skeleton.Skeleton, pagination.Result, error) {
 	// Check if have span on context
 	if span := opentracing.SpanFromContext(ctx); span != nil {
 		span := s.tracer.StartSpan("GetSkeleton", opentracing.ChildOf(span.Context()))
@@ -15,8 +20,14 @@ func (s Service) GetSkeleton(ctx context.Context) error {
 		ctx = opentracing.ContextWithSpan(ctx, span)
 	}
 
-	// checkPermission
-	// s.checkPermission(ctx, "")
+	if err := s.authorize(ctx, "skeleton:read", authz.Resource{Type: "skeleton"}); err != nil {
+		return nil, pagination.Result{}, errors.Wrap(err, "[SERVICE][GetSkeleton]")
+	}
+
+	skeletons, result, err := s.data.GetSkeletons(ctx, q)
+	if err != nil {
+		return nil, result, errors.Wrap(err, "[SERVICE][GetSkeleton]")
+	}
 
-	return nil
+	return skeletons, result, nil
 }
@@ -2,10 +2,14 @@ package skeleton
 
 import (
 	"context"
-	"errors"
 	"go-skeleton-auth/internal/entity"
 	"go-skeleton-auth/internal/entity/auth"
+	"go-skeleton-auth/internal/entity/errcode"
+	"go-skeleton-auth/internal/entity/skeleton"
+	"go-skeleton-auth/pkg/authz"
+	"go-skeleton-auth/pkg/errors"
 	jaegerLog "go-skeleton-auth/pkg/log"
+	"go-skeleton-auth/pkg/pagination"
 
 	"github.com/opentracing/opentracing-go"
 )
@@ -13,11 +17,19 @@ import (
 // Data ...
 // Masukkan function dari package data ke dalam interface ini
 type Data interface {
+	GetSkeletons(ctx context.Context, q pagination.Query) ([]skeleton.Skeleton, pagination.Result, error)
 }
 
 // AuthData ...
 type AuthData interface {
 	CheckAuth(ctx context.Context, _token, code string) (auth.Auth, error)
+	CheckAuthBatch(ctx context.Context, _token string, codes []string) (map[string]auth.Auth, error)
+}
+
+// Authorizer ...
+// Policy based authorization, see pkg/authz
+type Authorizer interface {
+	Authorize(ctx context.Context, req authz.Request) (authz.Decision, error)
 }
 
 // Service ...
@@ -25,36 +37,69 @@ type AuthData interface {
 type Service struct {
 	data     Data
 	authData AuthData
+	authz    Authorizer
 	tracer   opentracing.Tracer
 	logger   jaegerLog.Factory
 }
 
 // New ...
 // Tambahkan parameter sesuai banyak data layer yang dibutuhkan
-func New(data Data, authData AuthData, tracer opentracing.Tracer, logger jaegerLog.Factory) Service {
+func New(data Data, authData AuthData, authorizer Authorizer, tracer opentracing.Tracer, logger jaegerLog.Factory) Service {
 	// Assign variable dari parameter ke object
 	return Service{
 		data:     data,
 		authData: authData,
+		authz:    authorizer,
 		tracer:   tracer,
 		logger:   logger,
 	}
 }
 
 func (s Service) checkPermission(ctx context.Context, _permissions ...string) error {
-	claims := ctx.Value(entity.ContextKey("claims"))
-	if claims != nil {
-		actions := claims.(entity.ContextValue).Get("permissions").(map[string]interface{})
-		for _, action := range actions {
-			permissions := action.([]interface{})
-			for _, permission := range permissions {
-				for _, _permission := range _permissions {
-					if permission.(string) == _permission {
-						return nil
-					}
-				}
-			}
+	if !entity.HasPermission(ctx, _permissions...) {
+		return errors.NewCode(errcode.Forbidden, "forbidden")
+	}
+	return nil
+}
+
+// authorize checks whether the caller found in ctx may perform action on resource.
+// Callers authenticated with a JWT must also be granted action by the auth API.
+func (s Service) authorize(ctx context.Context, action string, resource authz.Resource) error {
+	req := authz.Request{
+		Principal: principalFromContext(ctx),
+		Action:    action,
+		Resource:  resource,
+	}
+
+	decision, err := s.authz.Authorize(ctx, req)
+	if err != nil {
+		return err
+	}
+	if !decision.Allowed {
+		return errors.NewCode(errcode.Forbidden, "forbidden")
+	}
+
+	if token, ok := ctx.Value(entity.ContextKey("token")).(string); ok && s.authData != nil {
+		if _, err := s.authData.CheckAuth(ctx, token, action); err != nil {
+			return err
 		}
 	}
-	return errors.New("401 unauthorized")
+	return nil
+}
+
+// principalFromContext builds the principal from the JWT claims stored by the middleware
+func principalFromContext(ctx context.Context) authz.Principal {
+	principal := authz.Principal{Attributes: map[string]interface{}{}}
+
+	claims, ok := ctx.Value(entity.ContextKey("claims")).(entity.ContextValue)
+	if !ok {
+		return principal
+	}
+	for key, val := range claims.M {
+		principal.Attributes[key] = val
+	}
+	if sub, ok := claims.Get("sub").(string); ok {
+		principal.ID = sub
+	}
+	return principal
 }
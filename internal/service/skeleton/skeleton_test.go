@@ -0,0 +1,110 @@
+package skeleton
+
+import (
+	"context"
+	"testing"
+
+	"go-skeleton-auth/internal/entity"
+	"go-skeleton-auth/internal/entity/auth"
+	"go-skeleton-auth/internal/entity/errcode"
+	"go-skeleton-auth/internal/entity/skeleton"
+	"go-skeleton-auth/pkg/authz"
+	"go-skeleton-auth/pkg/errors"
+	jaegerLog "go-skeleton-auth/pkg/log"
+	"go-skeleton-auth/pkg/pagination"
+
+	"github.com/opentracing/opentracing-go"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type fakeData struct{}
+
+func (fakeData) GetSkeletons(ctx context.Context, q pagination.Query) ([]skeleton.Skeleton, pagination.Result, error) {
+	return []skeleton.Skeleton{{SkeletonID: 1}}, pagination.Result{Total: 1}, nil
+}
+
+// fakeAuthData grants the codes in rights
+type fakeAuthData struct {
+	rights map[string]bool
+}
+
+func (f fakeAuthData) CheckAuth(ctx context.Context, _token, code string) (auth.Auth, error) {
+	if !f.rights[code] {
+		return auth.Auth{}, errors.NewCode(errcode.Forbidden, "forbidden")
+	}
+	return auth.Auth{}, nil
+}
+
+func (f fakeAuthData) CheckAuthBatch(ctx context.Context, _token string, codes []string) (map[string]auth.Auth, error) {
+	return nil, nil
+}
+
+func withClaims(claims map[string]interface{}) context.Context {
+	return context.WithValue(context.Background(), entity.ContextKey("claims"), entity.ContextValue{M: claims})
+}
+
+func TestGetSkeletonAuthorize(t *testing.T) {
+	policies, err := authz.ParsePolicies([]byte(`
+policies:
+    - name: read-skeleton
+      effect: allow
+      actions: ["skeleton:read"]
+      resources: ["skeleton"]
+      conditions:
+          - attr: principal.role
+            op: eq
+            value: reader
+`))
+	require.NoError(t, err)
+	logger := jaegerLog.NewFactory(zap.NewNop())
+	s := New(fakeData{}, nil, authz.NewEngine(policies, logger), opentracing.NoopTracer{}, logger)
+
+	skeletons, _, err := s.GetSkeleton(withClaims(map[string]interface{}{"sub": "1", "role": "reader"}), pagination.Query{})
+	require.NoError(t, err)
+	require.Len(t, skeletons, 1)
+
+	_, _, err = s.GetSkeleton(withClaims(map[string]interface{}{"sub": "2"}), pagination.Query{})
+	code, _ := errors.CodeOf(err)
+	require.Equal(t, errcode.Forbidden, code)
+}
+
+func TestGetSkeletonCheckRights(t *testing.T) {
+	logger := jaegerLog.NewFactory(zap.NewNop())
+	withToken := func(ctx context.Context) context.Context {
+		return context.WithValue(ctx, entity.ContextKey("token"), "jwt")
+	}
+
+	s := New(fakeData{}, fakeAuthData{rights: map[string]bool{"skeleton:read": true}}, authz.NewEngine(authz.AllowAll(), logger), opentracing.NoopTracer{}, logger)
+	_, _, err := s.GetSkeleton(withToken(withClaims(map[string]interface{}{"sub": "1"})), pagination.Query{})
+	require.NoError(t, err)
+
+	s = New(fakeData{}, fakeAuthData{}, authz.NewEngine(authz.AllowAll(), logger), opentracing.NoopTracer{}, logger)
+	_, _, err = s.GetSkeleton(withToken(withClaims(map[string]interface{}{"sub": "1"})), pagination.Query{})
+	code, _ := errors.CodeOf(err)
+	require.Equal(t, errcode.Forbidden, code)
+
+	// API key callers have no token to check
+	_, _, err = s.GetSkeleton(withClaims(map[string]interface{}{"sub": "apikey:7"}), pagination.Query{})
+	require.NoError(t, err)
+}
+
+func TestCheckPermission(t *testing.T) {
+	s := Service{}
+
+	ctx := withClaims(map[string]interface{}{
+		"permissions": map[string]interface{}{"skeleton": []interface{}{"skeleton:read"}},
+	})
+	require.NoError(t, s.checkPermission(ctx, "skeleton:read"))
+	require.Error(t, s.checkPermission(ctx, "skeleton:write"))
+
+	// tokens and client certificates without permissions are forbidden, not a panic
+	for _, ctx := range []context.Context{
+		context.Background(),
+		withClaims(map[string]interface{}{"sub": "1"}),
+		withClaims(map[string]interface{}{"sub": "cert:svc", "permissions": "skeleton:read"}),
+	} {
+		code, _ := errors.CodeOf(s.checkPermission(ctx, "skeleton:read"))
+		require.Equal(t, errcode.Forbidden, code)
+	}
+}
@@ -0,0 +1,99 @@
+package authz
+
+import (
+	"context"
+	"time"
+
+	jaegerLog "go-skeleton-auth/pkg/log"
+
+	"go.uber.org/zap"
+)
+
+// Authorizer decides whether a principal may perform an action on a resource
+type Authorizer interface {
+	Authorize(ctx context.Context, req Request) (Decision, error)
+}
+
+// Principal is the caller of an action, e.g. the JWT subject
+type Principal struct {
+	ID         string
+	Attributes map[string]interface{}
+}
+
+// Resource is the object an action is performed on
+type Resource struct {
+	Type       string
+	ID         string
+	Attributes map[string]interface{}
+}
+
+// Request is the input of an authorization decision
+type Request struct {
+	Principal Principal
+	Action    string
+	Resource  Resource
+	// Time is the time the request is evaluated at, time.Now() is used when empty
+	Time time.Time
+}
+
+// Decision is the result of an authorization request
+type Decision struct {
+	Allowed bool
+	// Policy is the name of the policy that produced the decision,
+	// empty when no policy matched and the default deny applies
+	Policy string
+	Reason string
+}
+
+// Engine is the built-in policy evaluator. Deny policies take precedence
+// over allow policies and a request that matches no policy is denied.
+type Engine struct {
+	policies []Policy
+	logger   jaegerLog.Factory
+}
+
+// NewEngine creates a policy engine that logs every decision to logger
+func NewEngine(policies []Policy, logger jaegerLog.Factory) *Engine {
+	return &Engine{
+		policies: policies,
+		logger:   logger,
+	}
+}
+
+// Authorize evaluates the request against all policies of the engine
+func (e *Engine) Authorize(ctx context.Context, req Request) (Decision, error) {
+	if req.Time.IsZero() {
+		req.Time = time.Now()
+	}
+
+	decision := Decision{Reason: "no matching policy"}
+	for _, p := range e.policies {
+		matched, err := p.matches(req)
+		if err != nil {
+			return Decision{}, err
+		}
+		if !matched {
+			continue
+		}
+
+		if p.Effect == EffectDeny {
+			decision = Decision{Allowed: false, Policy: p.Name, Reason: "denied by policy"}
+			break
+		}
+		if !decision.Allowed {
+			decision = Decision{Allowed: true, Policy: p.Name, Reason: "allowed by policy"}
+		}
+	}
+
+	e.logger.For(ctx).Info("Authorization decision",
+		zap.String("principal", req.Principal.ID),
+		zap.String("action", req.Action),
+		zap.String("resource.type", req.Resource.Type),
+		zap.String("resource.id", req.Resource.ID),
+		zap.Bool("allowed", decision.Allowed),
+		zap.String("policy", decision.Policy),
+		zap.String("reason", decision.Reason),
+	)
+
+	return decision, nil
+}
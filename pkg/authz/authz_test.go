@@ -0,0 +1,153 @@
+package authz
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	jaegerLog "go-skeleton-auth/pkg/log"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+const testPolicies = `
+policies:
+    - name: admin-weekdays
+      effect: allow
+      actions: ["*"]
+      resources: ["*"]
+      conditions:
+          - attr: principal.role
+            op: eq
+            value: admin
+          - attr: env.weekday
+            op: in
+            value: [Monday, Tuesday, Wednesday, Thursday, Friday]
+    - name: read-own-tenant-skeleton
+      effect: allow
+      actions: ["skeleton:read"]
+      resources: ["skeleton"]
+      conditions:
+          - attr: principal.tenant
+            op: eq
+            ref: resource.tenant
+    - name: deny-suspended
+      effect: deny
+      actions: ["*"]
+      resources: ["*"]
+      conditions:
+          - attr: principal.suspended
+            op: eq
+            value: true
+`
+
+func TestAuthorize(t *testing.T) {
+	policies, err := ParsePolicies([]byte(testPolicies))
+	require.NoError(t, err)
+	engine := NewEngine(policies, jaegerLog.NewFactory(zap.NewNop()))
+
+	var (
+		monday   = time.Date(2021, 3, 1, 10, 0, 0, 0, time.UTC)
+		saturday = time.Date(2021, 3, 6, 10, 0, 0, 0, time.UTC)
+		skeleton = Resource{Type: "skeleton", Attributes: map[string]interface{}{"tenant": 7}}
+	)
+
+	testCases := []struct {
+		name    string
+		req     Request
+		allowed bool
+		policy  string
+	}{
+		{
+			name: "admin on weekday",
+			req: Request{
+				Principal: Principal{ID: "1", Attributes: map[string]interface{}{"role": "admin"}},
+				Action:    "skeleton:delete",
+				Resource:  skeleton,
+				Time:      monday,
+			},
+			allowed: true,
+			policy:  "admin-weekdays",
+		},
+		{
+			name: "admin on weekend",
+			req: Request{
+				Principal: Principal{ID: "1", Attributes: map[string]interface{}{"role": "admin"}},
+				Action:    "skeleton:delete",
+				Resource:  skeleton,
+				Time:      saturday,
+			},
+			allowed: false,
+		},
+		{
+			name: "user reads own tenant",
+			req: Request{
+				Principal: Principal{ID: "2", Attributes: map[string]interface{}{"tenant": float64(7)}},
+				Action:    "skeleton:read",
+				Resource:  skeleton,
+				Time:      saturday,
+			},
+			allowed: true,
+			policy:  "read-own-tenant-skeleton",
+		},
+		{
+			name: "user reads other tenant",
+			req: Request{
+				Principal: Principal{ID: "2", Attributes: map[string]interface{}{"tenant": float64(8)}},
+				Action:    "skeleton:read",
+				Resource:  skeleton,
+				Time:      monday,
+			},
+			allowed: false,
+		},
+		{
+			name: "deny overrides allow",
+			req: Request{
+				Principal: Principal{ID: "1", Attributes: map[string]interface{}{"role": "admin", "suspended": true}},
+				Action:    "skeleton:read",
+				Resource:  skeleton,
+				Time:      monday,
+			},
+			allowed: false,
+			policy:  "deny-suspended",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			decision, err := engine.Authorize(context.Background(), tc.req)
+			require.NoError(t, err)
+			require.Equal(t, tc.allowed, decision.Allowed)
+			require.Equal(t, tc.policy, decision.Policy)
+		})
+	}
+}
+
+func TestParsePoliciesInvalid(t *testing.T) {
+	_, err := ParsePolicies([]byte(`
+policies:
+    - name: bad
+      effect: maybe
+`))
+	require.Error(t, err)
+
+	_, err = ParsePolicies([]byte(`
+policies:
+    - name: bad
+      effect: allow
+      conditions:
+          - attr: principal.role
+            op: like
+`))
+	require.Error(t, err)
+}
+
+func TestAllowAll(t *testing.T) {
+	engine := NewEngine(AllowAll(), jaegerLog.NewFactory(zap.NewNop()))
+
+	decision, err := engine.Authorize(context.Background(), Request{Action: "skeleton:delete", Resource: Resource{Type: "skeleton"}})
+	require.NoError(t, err)
+	require.True(t, decision.Allowed)
+	require.Equal(t, "allow-all", decision.Policy)
+}
@@ -0,0 +1,212 @@
+package authz
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path"
+	"reflect"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// Policy effects
+const (
+	EffectAllow = "allow"
+	EffectDeny  = "deny"
+)
+
+// Condition operators
+const (
+	OpEq     = "eq"
+	OpNe     = "ne"
+	OpIn     = "in"
+	OpNotIn  = "not_in"
+	OpExists = "exists"
+)
+
+// Policy grants or denies a set of actions on a set of resource types
+// when all of its conditions hold.
+// Actions and resources accept glob patterns, e.g. "skeleton:*" or "*".
+type Policy struct {
+	Name       string      `yaml:"name"`
+	Effect     string      `yaml:"effect"`
+	Actions    []string    `yaml:"actions"`
+	Resources  []string    `yaml:"resources"`
+	Conditions []Condition `yaml:"conditions"`
+}
+
+// Condition compares an attribute against a literal value or against another attribute.
+// Attributes are addressed as "principal.<name>", "resource.<name>", "action",
+// "env.weekday" (e.g. Monday) and "env.hour" (0-23).
+type Condition struct {
+	Attr  string      `yaml:"attr"`
+	Op    string      `yaml:"op"`
+	Value interface{} `yaml:"value"`
+	// Ref names an attribute to compare against instead of Value
+	Ref string `yaml:"ref"`
+}
+
+type policyFile struct {
+	Policies []Policy `yaml:"policies"`
+}
+
+// AllowAll returns a policy set that allows every action on every resource,
+// the behavior of services without authorization policies
+func AllowAll() []Policy {
+	return []Policy{{
+		Name:      "allow-all",
+		Effect:    EffectAllow,
+		Actions:   []string{"*"},
+		Resources: []string{"*"},
+	}}
+}
+
+// LoadPolicies reads policies from a YAML file
+func LoadPolicies(file string) ([]Policy, error) {
+	out, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	return ParsePolicies(out)
+}
+
+// ParsePolicies parses and validates policies from YAML
+func ParsePolicies(in []byte) ([]Policy, error) {
+	var pf policyFile
+	if err := yaml.Unmarshal(in, &pf); err != nil {
+		return nil, err
+	}
+
+	for _, p := range pf.Policies {
+		if err := p.validate(); err != nil {
+			return nil, err
+		}
+	}
+	return pf.Policies, nil
+}
+
+func (p Policy) validate() error {
+	if p.Effect != EffectAllow && p.Effect != EffectDeny {
+		return fmt.Errorf("policy %q: invalid effect %q", p.Name, p.Effect)
+	}
+	for _, pattern := range append(append([]string{}, p.Actions...), p.Resources...) {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("policy %q: invalid pattern %q", p.Name, pattern)
+		}
+	}
+	for _, c := range p.Conditions {
+		switch c.Op {
+		case OpEq, OpNe, OpIn, OpNotIn, OpExists:
+		default:
+			return fmt.Errorf("policy %q: invalid operator %q", p.Name, c.Op)
+		}
+	}
+	return nil
+}
+
+func (p Policy) matches(req Request) (bool, error) {
+	if !matchAny(p.Actions, req.Action) || !matchAny(p.Resources, req.Resource.Type) {
+		return false, nil
+	}
+
+	for _, c := range p.Conditions {
+		ok, err := c.eval(req)
+		if err != nil {
+			return false, fmt.Errorf("policy %q: %v", p.Name, err)
+		}
+		if !ok {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+func matchAny(patterns []string, s string) bool {
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, s); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func (c Condition) eval(req Request) (bool, error) {
+	attr, found := resolve(req, c.Attr)
+	if c.Op == OpExists {
+		return found, nil
+	}
+	if !found {
+		return false, nil
+	}
+
+	value := c.Value
+	if c.Ref != "" {
+		var ok bool
+		if value, ok = resolve(req, c.Ref); !ok {
+			return false, nil
+		}
+	}
+
+	switch c.Op {
+	case OpEq:
+		return equal(attr, value), nil
+	case OpNe:
+		return !equal(attr, value), nil
+	case OpIn, OpNotIn:
+		list, ok := value.([]interface{})
+		if !ok {
+			return false, fmt.Errorf("operator %q on %q requires a list", c.Op, c.Attr)
+		}
+		in := false
+		for _, v := range list {
+			if equal(attr, v) {
+				in = true
+				break
+			}
+		}
+		return in == (c.Op == OpIn), nil
+	}
+	return false, fmt.Errorf("invalid operator %q", c.Op)
+}
+
+func resolve(req Request, attr string) (interface{}, bool) {
+	scope, name := attr, ""
+	if i := strings.Index(attr, "."); i >= 0 {
+		scope, name = attr[:i], attr[i+1:]
+	}
+
+	switch scope {
+	case "action":
+		return req.Action, true
+	case "principal":
+		if name == "id" {
+			return req.Principal.ID, true
+		}
+		v, ok := req.Principal.Attributes[name]
+		return v, ok
+	case "resource":
+		switch name {
+		case "type":
+			return req.Resource.Type, true
+		case "id":
+			return req.Resource.ID, true
+		}
+		v, ok := req.Resource.Attributes[name]
+		return v, ok
+	case "env":
+		switch name {
+		case "weekday":
+			return req.Time.Weekday().String(), true
+		case "hour":
+			return req.Time.Hour(), true
+		}
+	}
+	return nil, false
+}
+
+// equal compares attribute values loosely so that values decoded from
+// JSON claims (float64) and YAML (int) compare as expected
+func equal(a, b interface{}) bool {
+	return reflect.DeepEqual(a, b) || fmt.Sprint(a) == fmt.Sprint(b)
+}
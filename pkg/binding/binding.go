@@ -0,0 +1,223 @@
+// Package binding decodes requests into structs and validates them.
+//
+// The JSON body is decoded into the struct, then fields tagged with path,
+// query or header are set from mux.Vars, the query string and the headers,
+// and finally the validate rules are checked, e.g.
+//
+//	type UpdateSkeletonRequest struct {
+//		ID      int      `path:"id" validate:"required,min=1"`
+//		DryRun  bool     `query:"dry_run"`
+//		Tenant  string   `header:"X-Tenant-ID" validate:"required"`
+//		Name    string   `json:"name" validate:"required,max=128"`
+//		Kind    string   `json:"kind" validate:"enum=small|large"`
+//		Tags    []string `query:"tag" validate:"max=5"`
+//	}
+//
+// See Validate for the rules.
+package binding
+
+import (
+	"encoding"
+	"encoding/json"
+	"io"
+	"mime"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"go-skeleton-auth/pkg/errors"
+	"go-skeleton-auth/pkg/response"
+
+	"github.com/gorilla/mux"
+)
+
+// Kinds of binding errors, compare them with errors.Cause
+var (
+	// ErrMalformed is a request that cannot be decoded, e.g. invalid JSON or
+	// a query parameter that is not a number, it is answered with 400
+	ErrMalformed = errors.New("malformed request")
+	// ErrInvalid is a well formed request breaking a validation rule,
+	// it is answered with 422
+	ErrInvalid = errors.New("invalid request")
+)
+
+// Error lists every field error of a request
+type Error struct {
+	// Kind is ErrMalformed or ErrInvalid
+	Kind   error
+	Fields []response.FieldError
+}
+
+func (e *Error) Error() string {
+	msgs := make([]string, len(e.Fields))
+	for i, f := range e.Fields {
+		msgs[i] = f.Field + " " + f.Msg
+	}
+	return e.Kind.Error() + ": " + strings.Join(msgs, "; ")
+}
+
+// Cause returns the kind of the error
+func (e *Error) Cause() error { return e.Kind }
+
+// FieldErrors returns the field errors of a binding error in the chain of err
+func FieldErrors(err error) []response.FieldError {
+	var e *Error
+	if errors.As(err, &e) {
+		return e.Fields
+	}
+	return nil
+}
+
+// Bind decodes r into dst, which must be a pointer to a struct, and validates it.
+// It returns an *Error listing every field error.
+func Bind(r *http.Request, dst interface{}) error {
+	v := reflect.ValueOf(dst)
+	if v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Struct {
+		return errors.Errorf("binding: %T is not a pointer to a struct", dst)
+	}
+
+	if err := decodeBody(r, dst); err != nil {
+		return &Error{Kind: ErrMalformed, Fields: []response.FieldError{
+			{Field: "body", Rule: "json", Msg: err.Error()},
+		}}
+	}
+
+	if fields := bindValues(r, v.Elem()); len(fields) > 0 {
+		return &Error{Kind: ErrMalformed, Fields: fields}
+	}
+
+	return Validate(dst)
+}
+
+// decodeBody decodes a JSON body, requests without body are left alone
+func decodeBody(r *http.Request, dst interface{}) error {
+	if r.Body == nil || r.Body == http.NoBody || r.ContentLength == 0 {
+		return nil
+	}
+	if ct := r.Header.Get("Content-Type"); ct != "" {
+		mediaType, _, err := mime.ParseMediaType(ct)
+		if err != nil || (mediaType != "application/json" && !strings.HasSuffix(mediaType, "+json")) {
+			return errors.Errorf("unsupported content type %q", ct)
+		}
+	}
+
+	err := json.NewDecoder(r.Body).Decode(dst)
+	if err == io.EOF {
+		return nil
+	}
+	return err
+}
+
+// bindValues sets the fields tagged with path, query or header
+func bindValues(r *http.Request, v reflect.Value) []response.FieldError {
+	var (
+		fields []response.FieldError
+		vars   = mux.Vars(r)
+		query  = r.URL.Query()
+		t      = v.Type()
+	)
+
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.PkgPath != "" {
+			continue
+		}
+
+		var (
+			name   string
+			values []string
+		)
+		if name = f.Tag.Get("path"); name != "" {
+			if val, ok := vars[name]; ok {
+				values = []string{val}
+			}
+		} else if name = f.Tag.Get("query"); name != "" {
+			values = query[name]
+		} else if name = f.Tag.Get("header"); name != "" {
+			values = r.Header.Values(name)
+		} else {
+			continue
+		}
+
+		if len(values) == 0 {
+			continue
+		}
+		if err := setValue(v.Field(i), values); err != nil {
+			fields = append(fields, response.FieldError{Field: name, Rule: "type", Msg: err.Error()})
+		}
+	}
+	return fields
+}
+
+var (
+	durationType        = reflect.TypeOf(time.Duration(0))
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// setValue converts the string values into the field, a slice takes them all
+func setValue(field reflect.Value, values []string) error {
+	if field.Kind() == reflect.Slice && field.Type().Elem().Kind() != reflect.Uint8 {
+		slice := reflect.MakeSlice(field.Type(), len(values), len(values))
+		for i, s := range values {
+			if err := setValue(slice.Index(i), []string{s}); err != nil {
+				return err
+			}
+		}
+		field.Set(slice)
+		return nil
+	}
+
+	s := values[0]
+	if field.Kind() == reflect.Ptr {
+		ptr := reflect.New(field.Type().Elem())
+		if err := setValue(ptr.Elem(), values); err != nil {
+			return err
+		}
+		field.Set(ptr)
+		return nil
+	}
+
+	if reflect.PtrTo(field.Type()).Implements(textUnmarshalerType) {
+		return field.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(s))
+	}
+
+	switch {
+	case field.Type() == durationType:
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return errors.Errorf("must be a duration")
+		}
+		field.SetInt(int64(d))
+	case field.Kind() == reflect.String:
+		field.SetString(s)
+	case field.Kind() == reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return errors.Errorf("must be a boolean")
+		}
+		field.SetBool(b)
+	case field.Kind() >= reflect.Int && field.Kind() <= reflect.Int64:
+		n, err := strconv.ParseInt(s, 10, field.Type().Bits())
+		if err != nil {
+			return errors.Errorf("must be an integer")
+		}
+		field.SetInt(n)
+	case field.Kind() >= reflect.Uint && field.Kind() <= reflect.Uint64:
+		n, err := strconv.ParseUint(s, 10, field.Type().Bits())
+		if err != nil {
+			return errors.Errorf("must be a positive integer")
+		}
+		field.SetUint(n)
+	case field.Kind() == reflect.Float32 || field.Kind() == reflect.Float64:
+		n, err := strconv.ParseFloat(s, field.Type().Bits())
+		if err != nil {
+			return errors.Errorf("must be a number")
+		}
+		field.SetFloat(n)
+	default:
+		return errors.Errorf("unsupported type %s", field.Type())
+	}
+	return nil
+}
@@ -0,0 +1,118 @@
+package binding
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"go-skeleton-auth/pkg/errors"
+	"go-skeleton-auth/pkg/response"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/require"
+)
+
+type address struct {
+	City string `json:"city" validate:"required"`
+}
+
+type updateRequest struct {
+	ID      int           `path:"id" validate:"required,min=1"`
+	DryRun  bool          `query:"dry_run"`
+	Tags    []string      `query:"tag" validate:"max=2"`
+	Timeout time.Duration `query:"timeout"`
+	Limit   *uint         `query:"limit"`
+	Tenant  string        `header:"X-Tenant-ID" validate:"required"`
+	Name    string        `json:"name" validate:"required,min=3,max=8"`
+	Kind    string        `json:"kind" validate:"enum=small|large"`
+	Code    string        `json:"code" validate:"regex=^[A-Z]{2,3}$"`
+	Address *address      `json:"address"`
+}
+
+func newRequest(target, body string, vars map[string]string) *http.Request {
+	r := httptest.NewRequest(http.MethodPut, target, strings.NewReader(body))
+	r.Header.Set("Content-Type", "application/json")
+	r.Header.Set("X-Tenant-ID", "t1")
+	return mux.SetURLVars(r, vars)
+}
+
+func TestBind(t *testing.T) {
+	r := newRequest("/skeletons/7?dry_run=true&tag=a&tag=b&timeout=2s&limit=5",
+		`{"name":"bone","kind":"large","code":"AB","address":{"city":"Jakarta"}}`,
+		map[string]string{"id": "7"})
+
+	var req updateRequest
+	require.NoError(t, Bind(r, &req))
+
+	limit := uint(5)
+	require.Equal(t, updateRequest{
+		ID:      7,
+		DryRun:  true,
+		Tags:    []string{"a", "b"},
+		Timeout: 2 * time.Second,
+		Limit:   &limit,
+		Tenant:  "t1",
+		Name:    "bone",
+		Kind:    "large",
+		Code:    "AB",
+		Address: &address{City: "Jakarta"},
+	}, req)
+}
+
+func TestBindMalformed(t *testing.T) {
+	var req updateRequest
+
+	err := Bind(newRequest("/skeletons/x", `{"name":`, map[string]string{"id": "x"}), &req)
+	require.Equal(t, ErrMalformed, errors.Cause(err))
+	require.Equal(t, "body", FieldErrors(err)[0].Field)
+
+	err = Bind(newRequest("/skeletons/x?dry_run=maybe", `{}`, map[string]string{"id": "x"}), &req)
+	require.Equal(t, ErrMalformed, errors.Cause(err))
+	require.Equal(t, []response.FieldError{
+		{Field: "id", Rule: "type", Msg: "must be an integer"},
+		{Field: "dry_run", Rule: "type", Msg: "must be a boolean"},
+	}, FieldErrors(err))
+
+	r := newRequest("/skeletons/1", `name=bone`, nil)
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	require.Equal(t, ErrMalformed, errors.Cause(Bind(r, &req)))
+
+	require.Error(t, Bind(r, req))
+}
+
+func TestBindInvalid(t *testing.T) {
+	r := newRequest("/skeletons/0?tag=a&tag=b&tag=c",
+		`{"name":"bo","kind":"medium","code":"abc","address":{}}`,
+		map[string]string{"id": "0"})
+	r.Header.Del("X-Tenant-ID")
+
+	var req updateRequest
+	err := Bind(r, &req)
+	require.Equal(t, ErrInvalid, errors.Cause(err))
+	require.Equal(t, []response.FieldError{
+		{Field: "id", Rule: "required", Msg: "is required"},
+		{Field: "tag", Rule: "max", Msg: "length must be at most 2"},
+		{Field: "X-Tenant-ID", Rule: "required", Msg: "is required"},
+		{Field: "name", Rule: "min", Msg: "length must be at least 3"},
+		{Field: "kind", Rule: "enum", Msg: "must be one of small, large"},
+		{Field: "code", Rule: "regex", Msg: "must match ^[A-Z]{2,3}$"},
+		{Field: "address.city", Rule: "required", Msg: "is required"},
+	}, FieldErrors(err))
+	require.Contains(t, err.Error(), "invalid request: id is required; tag length must be at most 2")
+}
+
+func TestValidateOptional(t *testing.T) {
+	// rules other than required are skipped for zero values
+	require.NoError(t, Validate(struct {
+		Kind  string `validate:"enum=a|b"`
+		Count int    `validate:"min=1"`
+		Code  string `validate:"regex=^x,y$"`
+	}{}))
+
+	err := Validate(&struct {
+		Code string `validate:"regex=^x,y$"`
+	}{Code: "x,z"})
+	require.Equal(t, "must match ^x,y$", FieldErrors(err)[0].Msg)
+}
@@ -0,0 +1,215 @@
+package binding
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+
+	"go-skeleton-auth/pkg/errors"
+	"go-skeleton-auth/pkg/response"
+)
+
+// Validate checks the validate rules of the fields of v, a struct or a pointer
+// to a struct, and of its nested structs. Rules are separated by commas:
+//
+//	required     the field is not its zero value
+//	min=N,max=N  bounds of numbers, or of the length of strings, slices and maps
+//	regex=RE     the string matches RE, it must be the last rule as RE may contain commas
+//	enum=a|b|c   the value is one of the listed ones
+//
+// Other rules are skipped for zero values of fields that are not required.
+// It returns an *Error of kind ErrInvalid listing every broken rule.
+func Validate(v interface{}) error {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return nil
+		}
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return errors.Errorf("binding: cannot validate %T", v)
+	}
+
+	var fields []response.FieldError
+	validateStruct(rv, "", &fields)
+	if len(fields) > 0 {
+		return &Error{Kind: ErrInvalid, Fields: fields}
+	}
+	return nil
+}
+
+func validateStruct(v reflect.Value, prefix string, fields *[]response.FieldError) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.PkgPath != "" {
+			continue
+		}
+		name := prefix + fieldName(f)
+		fv := v.Field(i)
+
+		if tag := f.Tag.Get("validate"); tag != "" {
+			for _, rule := range parseRules(tag) {
+				if msg := rule.check(fv); msg != "" {
+					*fields = append(*fields, response.FieldError{Field: name, Rule: rule.name, Msg: msg})
+				}
+			}
+		}
+
+		for fv.Kind() == reflect.Ptr && !fv.IsNil() {
+			fv = fv.Elem()
+		}
+		if fv.Kind() == reflect.Struct && fv.Type().PkgPath() != "time" {
+			validateStruct(fv, name+".", fields)
+		}
+	}
+}
+
+// fieldName is the name of the field in the request
+func fieldName(f reflect.StructField) string {
+	for _, key := range []string{"json", "path", "query", "header"} {
+		if name := strings.Split(f.Tag.Get(key), ",")[0]; name != "" && name != "-" {
+			return name
+		}
+	}
+	return f.Name
+}
+
+type rule struct {
+	name  string
+	param string
+}
+
+func parseRules(tag string) []rule {
+	var rules []rule
+	for tag != "" {
+		var part string
+		if strings.HasPrefix(tag, "regex=") {
+			part, tag = tag, ""
+		} else if i := strings.Index(tag, ","); i >= 0 {
+			part, tag = tag[:i], tag[i+1:]
+		} else {
+			part, tag = tag, ""
+		}
+
+		kv := strings.SplitN(strings.TrimSpace(part), "=", 2)
+		r := rule{name: kv[0]}
+		if len(kv) == 2 {
+			r.param = kv[1]
+		}
+		rules = append(rules, r)
+	}
+
+	// required is checked first, the other rules depend on it
+	sorted := rules[:0:0]
+	for _, r := range rules {
+		if r.name == "required" {
+			sorted = append([]rule{r}, sorted...)
+			continue
+		}
+		sorted = append(sorted, r)
+	}
+	return sorted
+}
+
+// check returns the error message when v breaks the rule, empty otherwise
+func (r rule) check(v reflect.Value) string {
+	if r.name == "required" {
+		if isZero(v) {
+			return "is required"
+		}
+		return ""
+	}
+	if isZero(v) {
+		return ""
+	}
+	for v.Kind() == reflect.Ptr {
+		v = v.Elem()
+	}
+
+	switch r.name {
+	case "min", "max":
+		return checkBound(r.name, r.param, v)
+	case "regex":
+		re, err := compile(r.param)
+		if err != nil {
+			return "has an invalid rule " + r.param
+		}
+		if v.Kind() != reflect.String || !re.MatchString(v.String()) {
+			return "must match " + r.param
+		}
+	case "enum":
+		values := strings.Split(r.param, "|")
+		s := fmt.Sprint(v.Interface())
+		for _, allowed := range values {
+			if s == allowed {
+				return ""
+			}
+		}
+		return "must be one of " + strings.Join(values, ", ")
+	default:
+		return "has an unknown rule " + r.name
+	}
+	return ""
+}
+
+func checkBound(name, param string, v reflect.Value) string {
+	bound, err := strconv.ParseFloat(param, 64)
+	if err != nil {
+		return "has an invalid rule " + name + "=" + param
+	}
+
+	var (
+		n        float64
+		property string
+	)
+	switch v.Kind() {
+	case reflect.String:
+		n, property = float64(len([]rune(v.String()))), "length "
+	case reflect.Slice, reflect.Map, reflect.Array:
+		n, property = float64(v.Len()), "length "
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n = float64(v.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n = float64(v.Uint())
+	case reflect.Float32, reflect.Float64:
+		n = v.Float()
+	default:
+		return "has an invalid rule " + name + " for " + v.Type().String()
+	}
+
+	if name == "min" && n < bound {
+		return property + "must be at least " + param
+	}
+	if name == "max" && n > bound {
+		return property + "must be at most " + param
+	}
+	return ""
+}
+
+func isZero(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Slice, reflect.Map:
+		return v.Len() == 0
+	}
+	return v.IsZero()
+}
+
+var patterns sync.Map
+
+// compile caches the compiled patterns of the rules
+func compile(pattern string) (*regexp.Regexp, error) {
+	if re, ok := patterns.Load(pattern); ok {
+		return re.(*regexp.Regexp), nil
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+	patterns.Store(pattern, re)
+	return re, nil
+}
@@ -0,0 +1,224 @@
+// Package compression compresses responses and decompresses gzip request bodies.
+//
+// Responses are compressed with gzip or deflate, as negotiated with Accept-Encoding,
+// when they are large enough and of a compressible content type.
+package compression
+
+import (
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"go-skeleton-auth/pkg/errors"
+	"go-skeleton-auth/pkg/response"
+)
+
+// Encodings supported, in order of preference
+const (
+	Gzip    = "gzip"
+	Deflate = "deflate"
+)
+
+// Errors of request bodies that cannot be decompressed
+var (
+	ErrUnsupportedEncoding = errors.New("unsupported Content-Encoding, use gzip")
+	ErrInvalidBody         = errors.New("invalid gzip request body")
+)
+
+// DefaultContentTypes are compressed when Options.ContentTypes is empty
+var DefaultContentTypes = []string{
+	"application/json",
+	"application/problem+json",
+	"application/javascript",
+	"application/xml",
+	"image/svg+xml",
+	"text/*",
+}
+
+// Options ...
+type Options struct {
+	// MinSize is the size from which responses are compressed, 1024 bytes when 0
+	MinSize int
+	// ContentTypes are the media types compressed, "type/*" matches every subtype.
+	// DefaultContentTypes when empty.
+	ContentTypes []string
+	// Level is the compression level, flate.DefaultCompression when 0
+	Level int
+	// MaxBodySize limits the size of decompressed request bodies, 10 MiB when 0
+	MaxBodySize int64
+	// ErrorHandler renders ErrUnsupportedEncoding and ErrInvalidBody, e.g. with
+	// the application error codes. They are rendered with their HTTP status when nil.
+	ErrorHandler func(w http.ResponseWriter, r *http.Request, err error)
+}
+
+// Compressor ...
+type Compressor struct {
+	opts  Options
+	gzips sync.Pool
+	// zlibs encode deflate, which HTTP defines as the zlib format (RFC 1950), not raw deflate
+	zlibs sync.Pool
+}
+
+// New ...
+func New(opts Options) *Compressor {
+	if opts.MinSize <= 0 {
+		opts.MinSize = 1024
+	}
+	if len(opts.ContentTypes) == 0 {
+		opts.ContentTypes = DefaultContentTypes
+	}
+	if opts.Level == 0 || opts.Level < flate.HuffmanOnly || opts.Level > flate.BestCompression {
+		opts.Level = flate.DefaultCompression
+	}
+	if opts.MaxBodySize <= 0 {
+		opts.MaxBodySize = 10 << 20
+	}
+	if opts.ErrorHandler == nil {
+		opts.ErrorHandler = renderError
+	}
+
+	c := &Compressor{opts: opts}
+	c.gzips.New = func() interface{} {
+		zw, _ := gzip.NewWriterLevel(nil, opts.Level)
+		return zw
+	}
+	c.zlibs.New = func() interface{} {
+		zw, _ := zlib.NewWriterLevel(nil, opts.Level)
+		return zw
+	}
+	return c
+}
+
+// Handler decompresses the request body and compresses the response of next
+func (c *Compressor) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !c.decompress(w, r) {
+			return
+		}
+
+		w.Header().Add("Vary", "Accept-Encoding")
+		encoding := negotiate(r.Header.Get("Accept-Encoding"))
+		if encoding == "" || r.Method == http.MethodHead || r.Header.Get("Range") != "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		cw := &writer{ResponseWriter: w, c: c, encoding: encoding}
+		defer cw.Close()
+		next.ServeHTTP(cw, r)
+	})
+}
+
+// decompress replaces a gzip request body by its content.
+// It renders the error and returns false when the body cannot be decompressed.
+func (c *Compressor) decompress(w http.ResponseWriter, r *http.Request) bool {
+	switch strings.ToLower(strings.TrimSpace(r.Header.Get("Content-Encoding"))) {
+	case "", "identity":
+		return true
+	case Gzip, "x-gzip":
+	default:
+		c.opts.ErrorHandler(w, r, ErrUnsupportedEncoding)
+		return false
+	}
+
+	zr, err := gzip.NewReader(r.Body)
+	if err != nil {
+		c.opts.ErrorHandler(w, r, errors.Wrap(ErrInvalidBody, err.Error()))
+		return false
+	}
+	r.Body = http.MaxBytesReader(w, zr, c.opts.MaxBodySize)
+	r.Header.Del("Content-Encoding")
+	r.Header.Del("Content-Length")
+	r.ContentLength = -1
+	return true
+}
+
+// renderError is the default ErrorHandler
+func renderError(w http.ResponseWriter, r *http.Request, err error) {
+	status, msg := http.StatusBadRequest, ErrInvalidBody.Error()
+	if errors.Is(err, ErrUnsupportedEncoding) {
+		status, msg = http.StatusUnsupportedMediaType, ErrUnsupportedEncoding.Error()
+	}
+	resp := response.Response{
+		StatusCode: status,
+		Error: response.Error{
+			Status: true,
+			Msg:    msg,
+			Code:   status,
+		},
+	}
+	resp.Render(w, r)
+}
+
+// compressible reports whether responses of contentType are compressed
+func (c *Compressor) compressible(contentType string) bool {
+	mediaType := strings.ToLower(strings.TrimSpace(strings.Split(contentType, ";")[0]))
+	for _, t := range c.opts.ContentTypes {
+		if t == mediaType || strings.HasSuffix(t, "/*") && strings.HasPrefix(mediaType, t[:len(t)-1]) {
+			return true
+		}
+	}
+	return false
+}
+
+// negotiate returns the preferred encoding accepted by acceptEncoding, "" for none
+func negotiate(acceptEncoding string) string {
+	var (
+		best  string
+		bestQ float64
+		q     = map[string]float64{}
+	)
+	for _, part := range strings.Split(acceptEncoding, ",") {
+		params := strings.Split(part, ";")
+		coding := strings.ToLower(strings.TrimSpace(params[0]))
+		if coding == "" {
+			continue
+		}
+		weight := 1.0
+		for _, p := range params[1:] {
+			if v := strings.TrimSpace(p); strings.HasPrefix(v, "q=") {
+				if f, err := strconv.ParseFloat(v[2:], 64); err == nil {
+					weight = f
+				}
+			}
+		}
+		q[coding] = weight
+	}
+
+	for _, coding := range []string{Gzip, Deflate} {
+		weight, ok := q[coding]
+		if !ok {
+			weight, ok = q["*"]
+		}
+		if ok && weight > bestQ {
+			best, bestQ = coding, weight
+		}
+	}
+	return best
+}
+
+// encoder returns a pooled encoder of encoding writing to w
+func (c *Compressor) encoder(encoding string, w io.Writer) io.WriteCloser {
+	if encoding == Gzip {
+		zw := c.gzips.Get().(*gzip.Writer)
+		zw.Reset(w)
+		return zw
+	}
+	zw := c.zlibs.Get().(*zlib.Writer)
+	zw.Reset(w)
+	return zw
+}
+
+func (c *Compressor) release(enc io.WriteCloser) {
+	switch e := enc.(type) {
+	case *gzip.Writer:
+		c.gzips.Put(e)
+	case *zlib.Writer:
+		c.zlibs.Put(e)
+	}
+}
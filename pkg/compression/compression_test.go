@@ -0,0 +1,147 @@
+package compression
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNegotiate(t *testing.T) {
+	testCases := map[string]string{
+		"":                      "",
+		"identity":              "",
+		"gzip":                  Gzip,
+		"deflate, gzip":         Gzip,
+		"gzip;q=0.5, deflate":   Deflate,
+		"gzip;q=0, deflate;q=0": "",
+		"*":                     Gzip,
+		"br, *;q=0.1":           Gzip,
+	}
+	for header, want := range testCases {
+		require.Equal(t, want, negotiate(header), header)
+	}
+}
+
+func TestHandler(t *testing.T) {
+	large := `{"data":"` + strings.Repeat("a", 2048) + `"}`
+	c := New(Options{})
+
+	testCases := []struct {
+		name           string
+		acceptEncoding string
+		contentType    string
+		body           string
+		encoding       string
+	}{
+		{"gzip", "gzip", "application/json", large, Gzip},
+		{"deflate", "deflate", "application/json; charset=utf-8", large, Deflate},
+		{"not accepted", "", "application/json", large, ""},
+		{"too small", "gzip", "application/json", `{"data":1}`, ""},
+		{"not compressible", "gzip", "image/png", large, ""},
+		{"wildcard content type", "gzip", "text/csv", large, Gzip},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			h := c.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", tc.contentType)
+				w.WriteHeader(http.StatusCreated)
+				// written in pieces to cross the threshold
+				io.WriteString(w, tc.body[:len(tc.body)/2])
+				io.WriteString(w, tc.body[len(tc.body)/2:])
+			}))
+
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			r.Header.Set("Accept-Encoding", tc.acceptEncoding)
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+
+			require.Equal(t, http.StatusCreated, w.Code)
+			require.Equal(t, "Accept-Encoding", w.Header().Get("Vary"))
+			require.Equal(t, tc.encoding, w.Header().Get("Content-Encoding"))
+
+			var body io.Reader = w.Body
+			switch tc.encoding {
+			case Gzip:
+				zr, err := gzip.NewReader(w.Body)
+				require.NoError(t, err)
+				body = zr
+			case Deflate:
+				zr, err := zlib.NewReader(w.Body)
+				require.NoError(t, err)
+				body = zr
+			}
+			b, err := io.ReadAll(body)
+			require.NoError(t, err)
+			require.Equal(t, tc.body, string(b))
+		})
+	}
+}
+
+func TestHandlerNoBody(t *testing.T) {
+	h := New(Options{}).Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	r := httptest.NewRequest(http.MethodDelete, "/", nil)
+	r.Header.Set("Accept-Encoding", "gzip")
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+
+	require.Equal(t, http.StatusNoContent, w.Code)
+	require.Empty(t, w.Header().Get("Content-Encoding"))
+	require.Zero(t, w.Body.Len())
+}
+
+func TestDecompress(t *testing.T) {
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	zw.Write([]byte(`{"jti":"a"}`))
+	zw.Close()
+
+	var got string
+	h := New(Options{MaxBodySize: 64}).Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		got = string(b)
+		require.Empty(t, r.Header.Get("Content-Encoding"))
+	}))
+
+	r := httptest.NewRequest(http.MethodPost, "/", &buf)
+	r.Header.Set("Content-Encoding", "gzip")
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Equal(t, `{"jti":"a"}`, got)
+
+	r = httptest.NewRequest(http.MethodPost, "/", strings.NewReader("not gzip"))
+	r.Header.Set("Content-Encoding", "gzip")
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	require.Equal(t, http.StatusBadRequest, w.Code)
+
+	r = httptest.NewRequest(http.MethodPost, "/", strings.NewReader("x"))
+	r.Header.Set("Content-Encoding", "br")
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	require.Equal(t, http.StatusUnsupportedMediaType, w.Code)
+	require.Contains(t, w.Header().Get("Content-Type"), "application/json")
+
+	var handled error
+	h = New(Options{ErrorHandler: func(w http.ResponseWriter, r *http.Request, err error) {
+		handled = err
+		w.WriteHeader(http.StatusTeapot)
+	}}).Handler(http.NotFoundHandler())
+	r = httptest.NewRequest(http.MethodPost, "/", strings.NewReader("not gzip"))
+	r.Header.Set("Content-Encoding", "gzip")
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	require.Equal(t, http.StatusTeapot, w.Code)
+	require.ErrorIs(t, handled, ErrInvalidBody)
+}
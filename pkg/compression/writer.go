@@ -0,0 +1,135 @@
+package compression
+
+import (
+	"io"
+	"net/http"
+)
+
+// writer buffers the beginning of a response until it knows whether to compress it
+type writer struct {
+	http.ResponseWriter
+	c        *Compressor
+	encoding string
+
+	status  int
+	buf     []byte
+	decided bool
+	// enc is set once the response is compressed
+	enc io.WriteCloser
+}
+
+func (w *writer) WriteHeader(status int) {
+	if status < http.StatusOK {
+		// informational responses come before the final one
+		w.ResponseWriter.WriteHeader(status)
+		return
+	}
+	if w.decided || w.status != 0 {
+		return
+	}
+	w.status = status
+	if !bodyAllowed(status) {
+		w.decide(false)
+	}
+}
+
+func (w *writer) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	if w.decided {
+		return w.write(b)
+	}
+
+	w.buf = append(w.buf, b...)
+	if len(w.buf) < w.c.opts.MinSize {
+		return len(b), nil
+	}
+	if err := w.flushBuf(w.shouldCompress()); err != nil {
+		return 0, err
+	}
+	return len(b), nil
+}
+
+// Flush implements http.Flusher, it compresses the response when it is allowed
+// whatever its size
+func (w *writer) Flush() {
+	if !w.decided && w.status != 0 {
+		w.flushBuf(w.shouldCompress())
+	}
+	if f, ok := w.enc.(interface{ Flush() error }); ok {
+		f.Flush()
+	}
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer
+func (w *writer) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// Close writes a response smaller than MinSize as is, and ends a compressed one
+func (w *writer) Close() error {
+	if !w.decided {
+		if w.status == 0 {
+			// nothing written, net/http answers 200
+			return nil
+		}
+		return w.flushBuf(false)
+	}
+	if w.enc == nil {
+		return nil
+	}
+	err := w.enc.Close()
+	w.c.release(w.enc)
+	w.enc = nil
+	return err
+}
+
+func (w *writer) shouldCompress() bool {
+	h := w.Header()
+	if h.Get("Content-Encoding") != "" {
+		return false
+	}
+	if h.Get("Content-Type") == "" {
+		// net/http would sniff the compressed bytes otherwise
+		h.Set("Content-Type", http.DetectContentType(w.buf))
+	}
+	return w.c.compressible(h.Get("Content-Type"))
+}
+
+func (w *writer) flushBuf(compress bool) error {
+	w.decide(compress)
+	buf := w.buf
+	w.buf = nil
+	if len(buf) == 0 {
+		return nil
+	}
+	_, err := w.write(buf)
+	return err
+}
+
+// decide writes the header, with Content-Encoding when compressing
+func (w *writer) decide(compress bool) {
+	w.decided = true
+	if compress {
+		h := w.Header()
+		h.Del("Content-Length")
+		h.Set("Content-Encoding", w.encoding)
+		w.enc = w.c.encoder(w.encoding, w.ResponseWriter)
+	}
+	w.ResponseWriter.WriteHeader(w.status)
+}
+
+func (w *writer) write(b []byte) (int, error) {
+	if w.enc != nil {
+		return w.enc.Write(b)
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+func bodyAllowed(status int) bool {
+	return status != http.StatusNoContent && status != http.StatusNotModified
+}
@@ -0,0 +1,92 @@
+// Package corspolicy configures CORS with github.com/rs/cors: origins with
+// wildcards that can be reloaded from a file, and policies overridden per route.
+package corspolicy
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/rs/cors"
+)
+
+// Policy is the CORS policy of a set of routes
+type Policy struct {
+	// Origins are the allowed origins, see Origins for the patterns
+	Origins          *Origins
+	AllowedMethods   []string
+	AllowedHeaders   []string
+	ExposedHeaders   []string
+	AllowCredentials bool
+	// MaxAge is how long preflight responses can be cached
+	MaxAge time.Duration
+}
+
+// Route overrides the default policy for the paths starting with PathPrefix.
+// A route policy without Origins allows the origins of the default policy.
+type Route struct {
+	PathPrefix string
+	Policy     Policy
+}
+
+// CORS applies the policy of the longest matching route prefix, or the default one
+type CORS struct {
+	def    *cors.Cors
+	routes []route
+}
+
+type route struct {
+	prefix string
+	cors   *cors.Cors
+}
+
+// New ...
+func New(def Policy, routes ...Route) *CORS {
+	if def.Origins == nil {
+		def.Origins = NewOrigins(nil)
+	}
+
+	c := &CORS{def: newCors(def)}
+	for _, r := range routes {
+		if r.Policy.Origins == nil {
+			r.Policy.Origins = def.Origins
+		}
+		c.routes = append(c.routes, route{prefix: r.PathPrefix, cors: newCors(r.Policy)})
+	}
+	sort.SliceStable(c.routes, func(i, j int) bool {
+		return len(c.routes[i].prefix) > len(c.routes[j].prefix)
+	})
+	return c
+}
+
+func newCors(p Policy) *cors.Cors {
+	return cors.New(cors.Options{
+		AllowOriginFunc:  p.Origins.Allowed,
+		AllowedMethods:   p.AllowedMethods,
+		AllowedHeaders:   p.AllowedHeaders,
+		ExposedHeaders:   p.ExposedHeaders,
+		AllowCredentials: p.AllowCredentials,
+		MaxAge:           int(p.MaxAge / time.Second),
+	})
+}
+
+// Handler applies the CORS policy of the request path. Preflight requests are
+// answered here, before routing, so routes need not accept OPTIONS.
+func (c *CORS) Handler(next http.Handler) http.Handler {
+	def := c.def.Handler(next)
+	routes := make([]http.Handler, len(c.routes))
+	for i, r := range c.routes {
+		routes[i] = r.cors.Handler(next)
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		for i, route := range c.routes {
+			if strings.HasPrefix(r.URL.Path, route.prefix) {
+				routes[i].ServeHTTP(w, r)
+				return
+			}
+		}
+		def.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,121 @@
+package corspolicy
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestOriginsAllowed(t *testing.T) {
+	o := NewOrigins([]string{"https://*.example.com", "http://localhost:*", "https://app.partner.io"})
+
+	for origin, allowed := range map[string]bool{
+		"https://www.example.com":       true,
+		"https://WWW.Example.com":       true,
+		"https://example.com":           false,
+		"http://www.example.com":        false,
+		"https://example.com.evil.io":   false,
+		"https://evil.io/.example.com":  false,
+		"http://localhost:3000":         true,
+		"https://app.partner.io":        true,
+		"https://app.partner.io.evil.x": false,
+	} {
+		require.Equal(t, allowed, o.Allowed(origin), origin)
+	}
+
+	require.False(t, NewOrigins(nil).Allowed("https://www.example.com"))
+	require.True(t, NewOrigins([]string{"*"}).Allowed("https://anything.io"))
+}
+
+func TestLoadOrigins(t *testing.T) {
+	dir, err := ioutil.TempDir("", "corspolicy")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	file := filepath.Join(dir, "origins.yaml")
+	require.NoError(t, ioutil.WriteFile(file, []byte("- https://a.example.com\n"), 0600))
+
+	o, err := LoadOrigins(file)
+	require.NoError(t, err)
+	require.True(t, o.Allowed("https://a.example.com"))
+	require.False(t, o.Allowed("https://b.example.com"))
+
+	o.interval = 0
+	require.NoError(t, ioutil.WriteFile(file, []byte("- https://b.example.com\n"), 0600))
+	later := time.Now().Add(time.Minute)
+	require.NoError(t, os.Chtimes(file, later, later))
+	require.True(t, o.Allowed("https://b.example.com"))
+	require.False(t, o.Allowed("https://a.example.com"))
+
+	// a broken file keeps the previous origins
+	require.NoError(t, ioutil.WriteFile(file, []byte("{not a list"), 0600))
+	later = later.Add(time.Minute)
+	require.NoError(t, os.Chtimes(file, later, later))
+	require.True(t, o.Allowed("https://b.example.com"))
+
+	_, err = LoadOrigins(filepath.Join(dir, "missing.yaml"))
+	require.Error(t, err)
+}
+
+func TestHandler(t *testing.T) {
+	c := New(Policy{
+		Origins:          NewOrigins([]string{"https://*.example.com"}),
+		AllowedMethods:   []string{"GET", "POST"},
+		AllowedHeaders:   []string{"Authorization"},
+		ExposedHeaders:   []string{"X-Request-ID"},
+		AllowCredentials: true,
+		MaxAge:           10 * time.Minute,
+	}, Route{
+		PathPrefix: "/example/admin",
+		Policy: Policy{
+			Origins:        NewOrigins([]string{"https://admin.example.com"}),
+			AllowedMethods: []string{"POST"},
+		},
+	}, Route{
+		PathPrefix: "/example/public",
+		Policy:     Policy{AllowedMethods: []string{"GET"}},
+	})
+
+	var called bool
+	h := c.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}))
+
+	preflight := func(path, origin, method string) http.Header {
+		r := httptest.NewRequest(http.MethodOptions, path, nil)
+		r.Header.Set("Origin", origin)
+		r.Header.Set("Access-Control-Request-Method", method)
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		return w.Header()
+	}
+
+	hdr := preflight("/example/skeleton", "https://www.example.com", "POST")
+	require.Equal(t, "https://www.example.com", hdr.Get("Access-Control-Allow-Origin"))
+	require.Equal(t, "true", hdr.Get("Access-Control-Allow-Credentials"))
+	require.Equal(t, "600", hdr.Get("Access-Control-Max-Age"))
+	require.False(t, called)
+
+	require.Empty(t, preflight("/example/skeleton", "https://evil.io", "POST").Get("Access-Control-Allow-Origin"))
+
+	// the admin routes only allow the admin origin
+	require.Empty(t, preflight("/example/admin/tokens/revoke", "https://www.example.com", "POST").Get("Access-Control-Allow-Origin"))
+	require.Equal(t, "https://admin.example.com", preflight("/example/admin/tokens/revoke", "https://admin.example.com", "POST").Get("Access-Control-Allow-Origin"))
+
+	// the public routes inherit the default origins but not the methods
+	require.Equal(t, "https://www.example.com", preflight("/example/public", "https://www.example.com", "GET").Get("Access-Control-Allow-Origin"))
+	require.Empty(t, preflight("/example/public", "https://www.example.com", "POST").Get("Access-Control-Allow-Origin"))
+
+	r := httptest.NewRequest(http.MethodGet, "/example/skeleton", nil)
+	r.Header.Set("Origin", "https://www.example.com")
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	require.True(t, called)
+	require.Equal(t, "X-Request-Id", w.Header().Get("Access-Control-Expose-Headers"))
+}
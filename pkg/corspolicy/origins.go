@@ -0,0 +1,123 @@
+package corspolicy
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"go-skeleton-auth/pkg/errors"
+
+	"gopkg.in/yaml.v2"
+)
+
+const defaultCheckInterval = 10 * time.Second
+
+// Origins is a list of allowed origin patterns that can change at runtime.
+// A pattern is an exact origin, "*" for any origin, or an origin with
+// wildcards, e.g. "https://*.example.com" or "http://localhost:*".
+type Origins struct {
+	file     string
+	interval time.Duration
+
+	mu       sync.Mutex
+	patterns []string
+	modTime  time.Time
+	checked  time.Time
+}
+
+// NewOrigins returns a fixed list of origins, it can be replaced with Set
+func NewOrigins(patterns []string) *Origins {
+	return &Origins{patterns: normalize(patterns)}
+}
+
+// LoadOrigins reads the origins from a YAML list in file, and reloads them
+// when the file changes on disk. The file is checked at most once per check
+// interval, during requests.
+func LoadOrigins(file string) (*Origins, error) {
+	o := &Origins{file: file, interval: defaultCheckInterval}
+	if err := o.reload(); err != nil {
+		return nil, err
+	}
+	return o, nil
+}
+
+// Set replaces the origins
+func (o *Origins) Set(patterns []string) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.patterns = normalize(patterns)
+}
+
+// Patterns returns the current origins
+func (o *Origins) Patterns() []string {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.checkLocked()
+	return append([]string{}, o.patterns...)
+}
+
+// Allowed reports whether origin matches one of the patterns
+func (o *Origins) Allowed(origin string) bool {
+	origin = strings.ToLower(origin)
+	for _, p := range o.Patterns() {
+		if p == "*" || p == origin {
+			return true
+		}
+		if ok, _ := path.Match(p, origin); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func (o *Origins) checkLocked() {
+	if o.file == "" || time.Since(o.checked) < o.interval {
+		return
+	}
+	// keep the previous origins when the new file is broken
+	_ = o.reloadLocked()
+}
+
+func (o *Origins) reload() error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return o.reloadLocked()
+}
+
+func (o *Origins) reloadLocked() error {
+	o.checked = time.Now()
+
+	info, err := os.Stat(o.file)
+	if err != nil {
+		return errors.Wrap(err, "corspolicy: stat origins file")
+	}
+	if !o.modTime.IsZero() && !info.ModTime().After(o.modTime) {
+		return nil
+	}
+
+	b, err := ioutil.ReadFile(o.file)
+	if err != nil {
+		return errors.Wrap(err, "corspolicy: read origins file")
+	}
+	var patterns []string
+	if err = yaml.Unmarshal(b, &patterns); err != nil {
+		return errors.Wrap(err, "corspolicy: parse origins file")
+	}
+
+	o.patterns = normalize(patterns)
+	o.modTime = info.ModTime()
+	return nil
+}
+
+func normalize(patterns []string) []string {
+	out := make([]string, 0, len(patterns))
+	for _, p := range patterns {
+		if p = strings.ToLower(strings.TrimSpace(p)); p != "" {
+			out = append(out, p)
+		}
+	}
+	return out
+}
@@ -0,0 +1,166 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+)
+
+// Code is an application error code. Codes are registered once with
+// Register together with how they are reported to clients and logged.
+type Code int
+
+// GRPCCode mirrors the status codes of google.golang.org/grpc/codes
+type GRPCCode uint32
+
+// gRPC status codes, see google.golang.org/grpc/codes
+const (
+	GRPCOK                 GRPCCode = 0
+	GRPCCanceled           GRPCCode = 1
+	GRPCUnknown            GRPCCode = 2
+	GRPCInvalidArgument    GRPCCode = 3
+	GRPCDeadlineExceeded   GRPCCode = 4
+	GRPCNotFound           GRPCCode = 5
+	GRPCAlreadyExists      GRPCCode = 6
+	GRPCPermissionDenied   GRPCCode = 7
+	GRPCResourceExhausted  GRPCCode = 8
+	GRPCFailedPrecondition GRPCCode = 9
+	GRPCAborted            GRPCCode = 10
+	GRPCOutOfRange         GRPCCode = 11
+	GRPCUnimplemented      GRPCCode = 12
+	GRPCInternal           GRPCCode = 13
+	GRPCUnavailable        GRPCCode = 14
+	GRPCDataLoss           GRPCCode = 15
+	GRPCUnauthenticated    GRPCCode = 16
+)
+
+// Level is the log level an error code is logged at
+type Level string
+
+// Log levels
+const (
+	LevelDebug Level = "debug"
+	LevelInfo  Level = "info"
+	LevelWarn  Level = "warn"
+	LevelError Level = "error"
+)
+
+// CodeInfo describes an application error code
+type CodeInfo struct {
+	Code       Code
+	HTTPStatus int
+	GRPCCode   GRPCCode
+	// Message is the default message shown to clients
+	Message string
+	Level   Level
+}
+
+// CodeUnknown is used for errors that carry no code
+const CodeUnknown Code = 0
+
+var (
+	registryMu sync.RWMutex
+	registry   = map[Code]CodeInfo{
+		CodeUnknown: {
+			Code:       CodeUnknown,
+			HTTPStatus: http.StatusInternalServerError,
+			GRPCCode:   GRPCUnknown,
+			Message:    "Internal server error",
+			Level:      LevelError,
+		},
+	}
+)
+
+// Register adds an error code to the registry and returns it, so codes can be
+// declared as package level variables. It panics when the code is already registered.
+func Register(info CodeInfo) Code {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	if _, ok := registry[info.Code]; ok {
+		panic(fmt.Sprintf("errors: code %d registered twice", info.Code))
+	}
+	if info.HTTPStatus == 0 {
+		info.HTTPStatus = http.StatusInternalServerError
+	}
+	if info.Level == "" {
+		info.Level = LevelError
+	}
+	registry[info.Code] = info
+	return info.Code
+}
+
+// Lookup returns the registration of code, the registration of
+// CodeUnknown is returned when code is not registered
+func Lookup(code Code) (CodeInfo, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	info, ok := registry[code]
+	if !ok {
+		return registry[CodeUnknown], false
+	}
+	return info, true
+}
+
+// CodeError is an error carrying an application error code.
+// Use errors.As or CodeOf to find it in a chain of wrapped errors.
+type CodeError struct {
+	Code Code
+	err  error
+}
+
+// WithCode annotates err with an application error code.
+// If err is nil, WithCode returns nil.
+func WithCode(err error, code Code) error {
+	if err == nil {
+		return nil
+	}
+	return &CodeError{Code: code, err: err}
+}
+
+// NewCode returns an error with the supplied code and message and records the stack trace
+func NewCode(code Code, message string) error {
+	return &CodeError{Code: code, err: &fundamental{msg: message, stack: callers()}}
+}
+
+func (c *CodeError) Error() string { return c.err.Error() }
+func (c *CodeError) Cause() error  { return c.err }
+func (c *CodeError) Unwrap() error { return c.err }
+
+// ErrorCode returns the application error code
+func (c *CodeError) ErrorCode() Code { return c.Code }
+
+func (c *CodeError) Format(s fmt.State, verb rune) {
+	switch verb {
+	case 'v':
+		if s.Flag('+') {
+			fmt.Fprintf(s, "%+v", c.err)
+			return
+		}
+		fallthrough
+	case 's':
+		io.WriteString(s, c.Error())
+	case 'q':
+		fmt.Fprintf(s, "%q", c.Error())
+	}
+}
+
+// CodeOf returns the code of the outermost error in the chain of err that
+// has one. Errors can carry a code by being a *CodeError or by implementing
+//
+//	type coder interface {
+//	       ErrorCode() Code
+//	}
+//
+// CodeUnknown and false are returned when no error in the chain has a code.
+func CodeOf(err error) (Code, bool) {
+	var c interface {
+		ErrorCode() Code
+	}
+	if As(err, &c) {
+		return c.ErrorCode(), true
+	}
+	return CodeUnknown, false
+}
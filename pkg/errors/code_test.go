@@ -0,0 +1,58 @@
+package errors
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+var testCode = Register(CodeInfo{
+	Code:       99001,
+	HTTPStatus: http.StatusConflict,
+	GRPCCode:   GRPCAlreadyExists,
+	Message:    "Already exists",
+	Level:      LevelInfo,
+})
+
+func TestCodeOf(t *testing.T) {
+	tests := []struct {
+		err  error
+		want Code
+		ok   bool
+	}{
+		{nil, CodeUnknown, false},
+		{New("plain"), CodeUnknown, false},
+		{NewCode(testCode, "conflict"), testCode, true},
+		{Wrap(WithCode(New("conflict"), testCode), "wrapped"), testCode, true},
+		{WithMessage(WithStack(NewCode(testCode, "conflict")), "wrapped"), testCode, true},
+		{fmt.Errorf("std: %w", NewCode(testCode, "conflict")), testCode, true},
+	}
+
+	for i, tt := range tests {
+		got, ok := CodeOf(tt.err)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("test %d: CodeOf(%v): got %v, %v, want %v, %v", i+1, tt.err, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestLookup(t *testing.T) {
+	info, ok := Lookup(testCode)
+	if !ok || info.HTTPStatus != http.StatusConflict || info.GRPCCode != GRPCAlreadyExists {
+		t.Errorf("Lookup(%v): got %+v, %v", testCode, info, ok)
+	}
+
+	info, ok = Lookup(Code(-1))
+	if ok || info.HTTPStatus != http.StatusInternalServerError {
+		t.Errorf("Lookup(-1): got %+v, %v, want the unknown code", info, ok)
+	}
+}
+
+func TestRegisterTwice(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("Register: expected a panic on a duplicate code")
+		}
+	}()
+	Register(CodeInfo{Code: testCode})
+}
@@ -0,0 +1,114 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"sort"
+)
+
+// WithFields annotates err with structured key/value pairs, e.g.
+//
+//	errors.WithFields(err, "user_id", id, "table", "users")
+//
+// Keys must be strings, a value without a key is dropped.
+// If err is nil, WithFields returns nil.
+func WithFields(err error, keyvals ...interface{}) error {
+	if err == nil {
+		return nil
+	}
+
+	fields := make(map[string]interface{}, len(keyvals)/2)
+	for i := 0; i+1 < len(keyvals); i += 2 {
+		key, ok := keyvals[i].(string)
+		if !ok {
+			key = fmt.Sprint(keyvals[i])
+		}
+		fields[key] = keyvals[i+1]
+	}
+	return &withFields{cause: err, fields: fields}
+}
+
+type withFields struct {
+	cause  error
+	fields map[string]interface{}
+}
+
+func (w *withFields) Error() string { return w.cause.Error() }
+func (w *withFields) Cause() error  { return w.cause }
+
+// Unwrap provides compatibility for Go 1.13 error chains.
+func (w *withFields) Unwrap() error { return w.cause }
+
+func (w *withFields) Format(s fmt.State, verb rune) {
+	switch verb {
+	case 'v':
+		if s.Flag('+') {
+			fmt.Fprintf(s, "%+v", w.cause)
+			keys := make([]string, 0, len(w.fields))
+			for k := range w.fields {
+				keys = append(keys, k)
+			}
+			sort.Strings(keys)
+			for _, k := range keys {
+				fmt.Fprintf(s, "\n%s=%v", k, w.fields[k])
+			}
+			return
+		}
+		fallthrough
+	case 's':
+		io.WriteString(s, w.Error())
+	case 'q':
+		fmt.Fprintf(s, "%q", w.Error())
+	}
+}
+
+// Fields returns the key/value pairs attached to err and every error it wraps.
+// When a key is set more than once, the outermost value wins.
+func Fields(err error) map[string]interface{} {
+	fields := make(map[string]interface{})
+	walk(err, func(err error) {
+		if w, ok := err.(*withFields); ok {
+			for k, v := range w.fields {
+				if _, set := fields[k]; !set {
+					fields[k] = v
+				}
+			}
+		}
+	})
+	return fields
+}
+
+// StackOf returns the stack trace recorded closest to the root cause of err,
+// nil when no error in the chain recorded one.
+func StackOf(err error) StackTrace {
+	type stackTracer interface {
+		StackTrace() StackTrace
+	}
+
+	var st StackTrace
+	walk(err, func(err error) {
+		if s, ok := err.(stackTracer); ok {
+			st = s.StackTrace()
+		}
+	})
+	return st
+}
+
+// walk calls fn for err and every error in its chain, depth first
+func walk(err error, fn func(error)) {
+	if err == nil {
+		return
+	}
+	fn(err)
+
+	switch e := err.(type) {
+	case interface{ Unwrap() []error }:
+		for _, err := range e.Unwrap() {
+			walk(err, fn)
+		}
+	case interface{ Unwrap() error }:
+		walk(e.Unwrap(), fn)
+	case interface{ Cause() error }:
+		walk(e.Cause(), fn)
+	}
+}
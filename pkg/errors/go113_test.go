@@ -0,0 +1,88 @@
+package errors
+
+import (
+	stderrors "errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsAs(t *testing.T) {
+	err := WithFields(Wrap(WithMessage(io.EOF, "read"), "[DATA][Read]"), "id", 1)
+
+	require.True(t, Is(err, io.EOF))
+	require.False(t, Is(err, io.ErrUnexpectedEOF))
+	require.Equal(t, io.EOF, Cause(err))
+
+	pathErr := &os.PathError{Op: "open", Path: "/x", Err: os.ErrNotExist}
+	var target *os.PathError
+	require.True(t, As(WithStack(pathErr), &target))
+	require.Equal(t, pathErr, target)
+	require.True(t, Is(WithStack(pathErr), os.ErrNotExist))
+
+	require.Equal(t, io.EOF, Unwrap(WithStack(io.EOF)))
+}
+
+func TestCodeErrorAs(t *testing.T) {
+	err := Wrap(WithCode(New("conflict"), testCode), "wrapped")
+
+	var ce *CodeError
+	require.True(t, stderrors.As(err, &ce))
+	require.Equal(t, testCode, ce.Code)
+	require.Equal(t, "wrapped: conflict", err.Error())
+}
+
+func TestJoin(t *testing.T) {
+	require.Nil(t, Join())
+	require.Nil(t, Join(nil, nil))
+	require.Equal(t, io.EOF, Join(nil, io.EOF))
+
+	coded := NewCode(testCode, "coded")
+	err := Join(io.EOF, Wrap(coded, "wrapped"))
+	require.Equal(t, "EOF; wrapped: coded", err.Error())
+	require.True(t, Is(err, io.EOF))
+	require.True(t, stderrors.Is(err, coded))
+
+	code, ok := CodeOf(err)
+	require.True(t, ok)
+	require.Equal(t, testCode, code)
+
+	// matched without the standard library following Unwrap() []error
+	j := err.(*joinError)
+	require.True(t, j.Is(io.EOF))
+	require.True(t, j.Is(coded))
+	require.False(t, j.Is(io.ErrUnexpectedEOF))
+	var codeErr *CodeError
+	require.True(t, j.As(&codeErr))
+	require.Equal(t, testCode, codeErr.Code)
+
+	verbose := fmt.Sprintf("%+v", err)
+	require.True(t, strings.HasPrefix(verbose, "EOF\n"))
+	require.Contains(t, verbose, "TestJoin")
+}
+
+func TestFields(t *testing.T) {
+	require.Nil(t, WithFields(nil, "k", "v"))
+
+	err := WithFields(Wrap(WithFields(New("boom"), "id", 1, "table", "users"), "[SERVICE][Get]"), "id", 2, "dangling")
+	require.Equal(t, "[SERVICE][Get]: boom", err.Error())
+	require.Equal(t, map[string]interface{}{"id": 2, "table": "users"}, Fields(err))
+	require.Empty(t, Fields(io.EOF))
+
+	joined := Join(WithFields(io.EOF, "a", 1), WithFields(io.EOF, "b", 2))
+	require.Equal(t, map[string]interface{}{"a": 1, "b": 2}, Fields(joined))
+
+	require.Contains(t, fmt.Sprintf("%+v", err), "\nid=2")
+}
+
+func TestStackOf(t *testing.T) {
+	require.Nil(t, StackOf(io.EOF))
+
+	root := New("root")
+	st := StackOf(Wrap(root, "outer"))
+	require.Equal(t, root.(*fundamental).StackTrace(), st)
+}
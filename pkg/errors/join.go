@@ -0,0 +1,85 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Join returns an error that wraps the given errors, e.g. to report every
+// failure of a batch. Nil errors are discarded and Join returns nil if all
+// errors are nil. A single non-nil error is returned as is.
+//
+// Is and As match any of the joined errors.
+func Join(errs ...error) error {
+	var nonNil []error
+	for _, err := range errs {
+		if err != nil {
+			nonNil = append(nonNil, err)
+		}
+	}
+
+	switch len(nonNil) {
+	case 0:
+		return nil
+	case 1:
+		return nonNil[0]
+	}
+	return &joinError{errs: nonNil}
+}
+
+type joinError struct {
+	errs []error
+}
+
+func (j *joinError) Error() string {
+	msgs := make([]string, len(j.errs))
+	for i, err := range j.errs {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// Unwrap returns the joined errors.
+func (j *joinError) Unwrap() []error { return j.errs }
+
+// Is reports whether any of the joined errors matches target.
+// The standard library only follows Unwrap() []error since Go 1.20.
+func (j *joinError) Is(target error) bool {
+	for _, err := range j.errs {
+		if Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// As finds the first of the joined errors that matches target.
+func (j *joinError) As(target interface{}) bool {
+	for _, err := range j.errs {
+		if As(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+func (j *joinError) Format(s fmt.State, verb rune) {
+	switch verb {
+	case 'v':
+		if s.Flag('+') {
+			for i, err := range j.errs {
+				if i > 0 {
+					io.WriteString(s, "\n")
+				}
+				fmt.Fprintf(s, "%+v", err)
+			}
+			return
+		}
+		fallthrough
+	case 's':
+		io.WriteString(s, j.Error())
+	case 'q':
+		fmt.Fprintf(s, "%q", j.Error())
+	}
+}
@@ -2,6 +2,7 @@ package grace
 
 import (
 	"context"
+	"crypto/tls"
 	"log"
 	"net"
 	"net/http"
@@ -11,14 +12,76 @@ import (
 	"time"
 )
 
+type option struct {
+	tlsConfig  *tls.Config
+	timeouts   Timeouts
+	onShutdown []func()
+}
+
+// Timeouts of the server, see http.Server
+type Timeouts struct {
+	// Read is the time to read a request, body included, 10s when 0
+	Read time.Duration
+	// ReadHeader is the time to read the request headers, Read when 0
+	ReadHeader time.Duration
+	// Write is the time from the end of the request headers to the end of the response,
+	// 10s when 0. It must exceed the request timeouts of the handlers.
+	Write time.Duration
+	// Idle is how long keep-alive connections wait for the next request, Read when 0
+	Idle time.Duration
+	// Drain is how long the server keeps serving after the shutdown signal, so that
+	// load balancers notice the failing readiness and stop sending requests
+	Drain time.Duration
+	// Shutdown is how long in-flight requests are waited for on shutdown, no limit when 0
+	Shutdown time.Duration
+}
+
+// Option ...
+type Option func(*option)
+
+// WithTLSConfig serves HTTPS with the given config instead of plain HTTP
+func WithTLSConfig(cfg *tls.Config) Option {
+	return func(opt *option) {
+		opt.tlsConfig = cfg
+	}
+}
+
+// WithTimeouts overrides the default timeouts
+func WithTimeouts(t Timeouts) Option {
+	return func(opt *option) {
+		opt.timeouts = t
+	}
+}
+
+// WithOnShutdown calls fn when the shutdown signal is received, before draining
+func WithOnShutdown(fn func()) Option {
+	return func(opt *option) {
+		opt.onShutdown = append(opt.onShutdown, fn)
+	}
+}
+
 // Serve will run HTTP server with graceful shutdown capability
-func Serve(port string, h http.Handler) error {
+func Serve(port string, h http.Handler, opts ...Option) error {
+	opt := &option{}
+	for _, optFunc := range opts {
+		optFunc(opt)
+	}
 
 	// create new http server object
+	t := opt.timeouts
+	if t.Read <= 0 {
+		t.Read = 10 * time.Second
+	}
+	if t.Write <= 0 {
+		t.Write = 10 * time.Second
+	}
 	server := &http.Server{
-		ReadTimeout:  10 * time.Second,
-		WriteTimeout: 10 * time.Second,
-		Handler:      h,
+		ReadTimeout:       t.Read,
+		ReadHeaderTimeout: t.ReadHeader,
+		WriteTimeout:      t.Write,
+		IdleTimeout:       t.Idle,
+		Handler:           h,
+		TLSConfig:         opt.tlsConfig,
 	}
 
 	lis, err := net.Listen("tcp", port)
@@ -34,8 +97,22 @@ func Serve(port string, h http.Handler) error {
 		signal.Notify(signals, os.Interrupt, syscall.SIGTERM, syscall.SIGHUP)
 		<-signals
 
-		// We received an os signal, shut down.
-		if err := server.Shutdown(context.Background()); err != nil {
+		// We received an os signal, drain then shut down.
+		for _, fn := range opt.onShutdown {
+			fn()
+		}
+		if t.Drain > 0 {
+			log.Println("HTTP server draining for", t.Drain)
+			time.Sleep(t.Drain)
+		}
+
+		ctx := context.Background()
+		if t.Shutdown > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, t.Shutdown)
+			defer cancel()
+		}
+		if err := server.Shutdown(ctx); err != nil {
 			// Error from closing listeners, or context timeout:
 			log.Printf("HTTP server shutdown error: %v", err)
 		}
@@ -44,7 +121,13 @@ func Serve(port string, h http.Handler) error {
 	}()
 
 	log.Println("HTTP server running on port", port)
-	if err := server.Serve(lis); err != http.ErrServerClosed {
+	if opt.tlsConfig != nil {
+		// certificates come from the TLS config
+		err = server.ServeTLS(lis, "", "")
+	} else {
+		err = server.Serve(lis)
+	}
+	if err != http.ErrServerClosed {
 		// Error starting or closing listener:
 		return err
 	}
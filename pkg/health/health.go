@@ -0,0 +1,131 @@
+// Package health checks the dependencies of the service for its readiness probe.
+package health
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Statuses of a report or of a check
+const (
+	StatusOK       = "ok"
+	StatusFail     = "fail"
+	StatusDraining = "draining"
+)
+
+// Check reports the health of a dependency, nil when it is healthy.
+// It must return when ctx is done.
+type Check func(ctx context.Context) error
+
+// Result is the outcome of a check
+type Result struct {
+	Status    string  `json:"status"`
+	LatencyMS float64 `json:"latency_ms"`
+	// Err is the failure of the check, it is meant for the logs and never
+	// rendered as it may expose hosts or credentials to unauthenticated callers
+	Err error `json:"-"`
+}
+
+// Report is the outcome of every check
+type Report struct {
+	Status string            `json:"status"`
+	Checks map[string]Result `json:"checks,omitempty"`
+}
+
+// OK reports whether the service is ready
+func (r Report) OK() bool {
+	return r.Status == StatusOK
+}
+
+// Checker is the registry of the checks of the dependencies
+type Checker struct {
+	timeout  time.Duration
+	draining int32
+
+	mu     sync.RWMutex
+	checks map[string]Check
+}
+
+// New returns a Checker giving each check timeout, 2s when 0
+func New(timeout time.Duration) *Checker {
+	if timeout <= 0 {
+		timeout = 2 * time.Second
+	}
+	return &Checker{timeout: timeout, checks: make(map[string]Check)}
+}
+
+// Register adds or replaces the check of the dependency name
+func (c *Checker) Register(name string, check Check) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.checks[name] = check
+}
+
+// Drain makes the service not ready for good, e.g. on shutdown so that
+// load balancers stop sending requests before the server closes
+func (c *Checker) Drain() {
+	atomic.StoreInt32(&c.draining, 1)
+}
+
+// Draining reports whether Drain was called
+func (c *Checker) Draining() bool {
+	return atomic.LoadInt32(&c.draining) == 1
+}
+
+// Check runs the checks concurrently. The service is ready when every check
+// passes and it is not draining.
+func (c *Checker) Check(ctx context.Context) Report {
+	if c.Draining() {
+		return Report{Status: StatusDraining}
+	}
+
+	c.mu.RLock()
+	checks := make(map[string]Check, len(c.checks))
+	for name, check := range c.checks {
+		checks[name] = check
+	}
+	c.mu.RUnlock()
+
+	var (
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		results = make(map[string]Result, len(checks))
+	)
+	for name, check := range checks {
+		wg.Add(1)
+		go func(name string, check Check) {
+			defer wg.Done()
+			res := c.run(ctx, check)
+			mu.Lock()
+			results[name] = res
+			mu.Unlock()
+		}(name, check)
+	}
+	wg.Wait()
+
+	report := Report{Status: StatusOK, Checks: results}
+	for _, res := range results {
+		if res.Status != StatusOK {
+			report.Status = StatusFail
+		}
+	}
+	return report
+}
+
+func (c *Checker) run(ctx context.Context, check Check) Result {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	start := time.Now()
+	err := check(ctx)
+	res := Result{
+		Status:    StatusOK,
+		LatencyMS: float64(time.Since(start).Microseconds()) / 1000,
+	}
+	if err != nil {
+		res.Status, res.Err = StatusFail, err
+	}
+	return res
+}
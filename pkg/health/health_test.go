@@ -0,0 +1,38 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestChecker(t *testing.T) {
+	c := New(20 * time.Millisecond)
+	c.Register("db", func(ctx context.Context) error { return nil })
+
+	report := c.Check(context.Background())
+	require.True(t, report.OK())
+	require.Equal(t, StatusOK, report.Checks["db"].Status)
+
+	c.Register("auth", func(ctx context.Context) error { return errors.New("connection refused") })
+	c.Register("slow", func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+
+	report = c.Check(context.Background())
+	require.False(t, report.OK())
+	require.Equal(t, StatusFail, report.Status)
+	require.Equal(t, StatusOK, report.Checks["db"].Status)
+	require.Equal(t, StatusFail, report.Checks["auth"].Status)
+	require.EqualError(t, report.Checks["auth"].Err, "connection refused")
+	require.ErrorIs(t, report.Checks["slow"].Err, context.DeadlineExceeded)
+	require.GreaterOrEqual(t, report.Checks["slow"].LatencyMS, 20.0)
+
+	c.Drain()
+	report = c.Check(context.Background())
+	require.Equal(t, Report{Status: StatusDraining}, report)
+}
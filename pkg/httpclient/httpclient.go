@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"compress/gzip"
 	"context"
+	"crypto/tls"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -12,6 +13,8 @@ import (
 	"net/url"
 	"time"
 
+	"go-skeleton-auth/pkg/requestid"
+
 	"github.com/afex/hystrix-go/hystrix"
 	"github.com/google/go-querystring/query"
 	"github.com/opentracing/opentracing-go"
@@ -63,8 +66,21 @@ type Client struct {
 	tracer opentracing.Tracer
 }
 
+// Option ...
+type Option func(*Client)
+
+// WithTLSConfig makes the client use its own transport with the given TLS config,
+// e.g. to present a client certificate for mutual TLS
+func WithTLSConfig(cfg *tls.Config) Option {
+	return func(c *Client) {
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		transport.TLSClientConfig = cfg
+		c.client = &http.Client{Transport: transport}
+	}
+}
+
 // NewClient creates new Client object with given options
-func NewClient(tracer opentracing.Tracer) *Client {
+func NewClient(tracer opentracing.Tracer, opts ...Option) *Client {
 	c := Client{
 		name:                  xid.New().String(),
 		httpTimeout:           defaultHTTPTimeout * time.Second,
@@ -77,6 +93,10 @@ func NewClient(tracer opentracing.Tracer) *Client {
 	c.client = sharedClient
 	c.tracer = tracer
 
+	for _, opt := range opts {
+		opt(&c)
+	}
+
 	hystrix.ConfigureCommand(c.name, hystrix.CommandConfig{
 		Timeout:                int(c.httpTimeout.Nanoseconds()) / 1e6,
 		MaxConcurrentRequests:  c.maxConcurrentReq,
@@ -94,7 +114,11 @@ func (c *Client) do(req *http.Request) (*http.Response, error) {
 		err  error
 	)
 
-	err = hystrix.Do(c.name, func() error {
+	// forward the request ID of the incoming request
+	requestid.Inject(req.Context(), req.Header)
+
+	// the request gives up at the deadline of its context, if sooner than the client timeout
+	err = hystrix.DoC(req.Context(), c.name, func(ctx context.Context) error {
 		resp, err = c.client.Do(req)
 		return err
 	}, nil)
@@ -299,6 +323,7 @@ func (c *Client) DoURLQueryJSON(ctx context.Context, method string, path string,
 		return
 	}
 
+	req = req.WithContext(ctx)
 	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
 
 	resp, err = c.Do(req)
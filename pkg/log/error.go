@@ -0,0 +1,67 @@
+package log
+
+import (
+	"fmt"
+	"sort"
+
+	"go-skeleton-auth/pkg/errors"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// ErrorFields renders err as flat fields: the message under key, and when the
+// error chain carries them the stack trace (key.stack), the application code
+// (key.code) and the structured fields attached with errors.WithFields (key.<name>).
+// Flat fields are used so that they survive the conversion to span logs.
+func ErrorFields(key string, err error) []zapcore.Field {
+	if err == nil {
+		return nil
+	}
+
+	fields := []zapcore.Field{zap.String(key, err.Error())}
+	if code, ok := errors.CodeOf(err); ok {
+		fields = append(fields, zap.Int(key+".code", int(code)))
+	}
+
+	attrs := errors.Fields(err)
+	names := make([]string, 0, len(attrs))
+	for name := range attrs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fields = append(fields, zap.Any(key+"."+name, attrs[name]))
+	}
+
+	if st := errors.StackOf(err); st != nil {
+		fields = append(fields, zap.String(key+".stack", fmt.Sprintf("%+v", st)))
+	}
+	return fields
+}
+
+// encodeErrors expands the zap.Error fields with ErrorFields,
+// other fields are kept as they are
+func encodeErrors(fields []zapcore.Field) []zapcore.Field {
+	expand := false
+	for _, field := range fields {
+		if field.Type == zapcore.ErrorType {
+			expand = true
+			break
+		}
+	}
+	if !expand {
+		return fields
+	}
+
+	encoded := make([]zapcore.Field, 0, len(fields)+4)
+	for _, field := range fields {
+		err, ok := field.Interface.(error)
+		if field.Type != zapcore.ErrorType || !ok {
+			encoded = append(encoded, field)
+			continue
+		}
+		encoded = append(encoded, ErrorFields(field.Key, err)...)
+	}
+	return encoded
+}
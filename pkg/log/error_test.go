@@ -0,0 +1,29 @@
+package log
+
+import (
+	"io"
+	"testing"
+
+	"go-skeleton-auth/pkg/errors"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestErrorFields(t *testing.T) {
+	core, logs := observer.New(zap.InfoLevel)
+	l := logger{logger: zap.New(core)}
+
+	err := errors.WithFields(errors.Wrap(io.EOF, "[DATA][Read]"), "id", 42)
+	l.Error("read failed", zap.Error(err), zap.String("other", "x"))
+
+	require.Equal(t, 1, logs.Len())
+	fields := logs.All()[0].ContextMap()
+	require.Equal(t, "[DATA][Read]: EOF", fields["error"])
+	require.Equal(t, int64(42), fields["error.id"])
+	require.Equal(t, "x", fields["other"])
+	require.Contains(t, fields["error.stack"], "TestErrorFields")
+
+	require.Nil(t, ErrorFields("error", nil))
+}
@@ -3,6 +3,8 @@ package log
 import (
 	"context"
 
+	"go-skeleton-auth/pkg/requestid"
+
 	"github.com/opentracing/opentracing-go"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
@@ -26,13 +28,23 @@ func (b Factory) Bg() Logger {
 
 // For returns a context-aware Logger. If the context
 // contains an OpenTracing span, all logging calls are also
-// echo-ed into the span.
+// echo-ed into the span. If it carries a request ID, it is
+// added to the logger fields and tagged on the span.
 func (b Factory) For(ctx context.Context) Logger {
+	zl := b.logger
+	id := requestid.FromContext(ctx)
+	if id != "" {
+		zl = zl.With(zap.String("request_id", id))
+	}
+
 	if span := opentracing.SpanFromContext(ctx); span != nil {
+		if id != "" {
+			span.SetTag("request.id", id)
+		}
 		// TODO for Jaeger span extract trace/span IDs as fields
-		return spanLogger{span: span, logger: b.logger}
+		return spanLogger{span: span, logger: zl}
 	}
-	return b.Bg()
+	return logger{logger: zl}
 }
 
 // With creates a child logger, and optionally adds some context fields to that logger.
@@ -0,0 +1,34 @@
+package log
+
+import (
+	"context"
+	"testing"
+
+	"go-skeleton-auth/pkg/requestid"
+
+	"github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/mocktracer"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestForRequestID(t *testing.T) {
+	core, logs := observer.New(zap.InfoLevel)
+	f := NewFactory(zap.New(core))
+
+	tracer := mocktracer.New()
+	span := tracer.StartSpan("test")
+	ctx := opentracing.ContextWithSpan(requestid.NewContext(context.Background(), "req-1"), span)
+
+	f.For(ctx).Info("with span")
+	f.For(requestid.NewContext(context.Background(), "req-2")).Info("without span")
+	f.For(context.Background()).Info("without request ID")
+	span.Finish()
+
+	entries := logs.All()
+	require.Equal(t, "req-1", entries[0].ContextMap()["request_id"])
+	require.Equal(t, "req-2", entries[1].ContextMap()["request_id"])
+	require.NotContains(t, entries[2].ContextMap(), "request_id")
+	require.Equal(t, "req-1", tracer.FinishedSpans()[0].Tag("request.id"))
+}
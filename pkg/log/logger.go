@@ -5,9 +5,12 @@ import (
 	"go.uber.org/zap/zapcore"
 )
 
-// Logger is a simplified abstraction of the zap.Logger
+// Logger is a simplified abstraction of the zap.Logger.
+// Errors logged with zap.Error are expanded with ErrorFields.
 type Logger interface {
+	Debug(msg string, fields ...zapcore.Field)
 	Info(msg string, fields ...zapcore.Field)
+	Warn(msg string, fields ...zapcore.Field)
 	Error(msg string, fields ...zapcore.Field)
 	Fatal(msg string, fields ...zapcore.Field)
 	With(fields ...zapcore.Field) Logger
@@ -18,22 +21,38 @@ type logger struct {
 	logger *zap.Logger
 }
 
+// Debug logs a debug msg with fields
+func (l logger) Debug(msg string, fields ...zapcore.Field) {
+	fields = encodeErrors(fields)
+	l.logger.Debug(msg, fields...)
+}
+
 // Info logs an info msg with fields
 func (l logger) Info(msg string, fields ...zapcore.Field) {
+	fields = encodeErrors(fields)
 	l.logger.Info(msg, fields...)
 }
 
+// Warn logs a warning msg with fields
+func (l logger) Warn(msg string, fields ...zapcore.Field) {
+	fields = encodeErrors(fields)
+	l.logger.Warn(msg, fields...)
+}
+
 // Error logs an error msg with fields
 func (l logger) Error(msg string, fields ...zapcore.Field) {
+	fields = encodeErrors(fields)
 	l.logger.Error(msg, fields...)
 }
 
 // Fatal logs a fatal error msg with fields
 func (l logger) Fatal(msg string, fields ...zapcore.Field) {
+	fields = encodeErrors(fields)
 	l.logger.Fatal(msg, fields...)
 }
 
 // With creates a child logger, and optionally adds some context fields to that logger.
 func (l logger) With(fields ...zapcore.Field) Logger {
+	fields = encodeErrors(fields)
 	return logger{logger: l.logger.With(fields...)}
 }
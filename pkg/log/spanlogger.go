@@ -15,17 +15,32 @@ type spanLogger struct {
 	span   opentracing.Span
 }
 
+func (sl spanLogger) Debug(msg string, fields ...zapcore.Field) {
+	fields = encodeErrors(fields)
+	sl.logToSpan("debug", msg, fields...)
+	sl.logger.Debug(msg, fields...)
+}
+
 func (sl spanLogger) Info(msg string, fields ...zapcore.Field) {
+	fields = encodeErrors(fields)
 	sl.logToSpan("info", msg, fields...)
 	sl.logger.Info(msg, fields...)
 }
 
+func (sl spanLogger) Warn(msg string, fields ...zapcore.Field) {
+	fields = encodeErrors(fields)
+	sl.logToSpan("warn", msg, fields...)
+	sl.logger.Warn(msg, fields...)
+}
+
 func (sl spanLogger) Error(msg string, fields ...zapcore.Field) {
+	fields = encodeErrors(fields)
 	sl.logToSpan("error", msg, fields...)
 	sl.logger.Error(msg, fields...)
 }
 
 func (sl spanLogger) Fatal(msg string, fields ...zapcore.Field) {
+	fields = encodeErrors(fields)
 	sl.logToSpan("fatal", msg, fields...)
 	tag.Error.Set(sl.span, true)
 	sl.logger.Fatal(msg, fields...)
@@ -33,6 +48,7 @@ func (sl spanLogger) Fatal(msg string, fields ...zapcore.Field) {
 
 // With creates a child logger, and optionally adds some context fields to that logger.
 func (sl spanLogger) With(fields ...zapcore.Field) Logger {
+	fields = encodeErrors(fields)
 	return spanLogger{logger: sl.logger.With(fields...), span: sl.span}
 }
 
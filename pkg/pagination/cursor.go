@@ -0,0 +1,90 @@
+package pagination
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"go-skeleton-auth/pkg/errors"
+)
+
+// Cursor is the position after the last item of a page: the values of its
+// sort fields. It is opaque to clients.
+type Cursor struct {
+	// Sort is the sort the values belong to, e.g. "-created_at,id"
+	Sort   string        `json:"s"`
+	Values []interface{} `json:"v"`
+	// Times are the indexes of the values that are times, they are decoded
+	// back to time.Time so the driver binds them in the connection location
+	Times []int `json:"t,omitempty"`
+}
+
+// NextCursor returns the cursor after the item whose field values are given
+// by value, e.g. the last item of a page
+func (q Query) NextCursor(value func(field string) interface{}) string {
+	c := Cursor{Sort: sortString(q.Sort)}
+	for i, s := range q.Sort {
+		v := value(s.Field)
+		if t, ok := v.(time.Time); ok {
+			v = t.Format(time.RFC3339Nano)
+			c.Times = append(c.Times, i)
+		}
+		c.Values = append(c.Values, v)
+	}
+
+	b, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// decodeCursor decodes a cursor, an empty one is the start of the list
+func decodeCursor(s string, sorts []Sort) (*Cursor, error) {
+	c := &Cursor{Sort: sortString(sorts)}
+	if s == "" {
+		return c, nil
+	}
+
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, errors.Wrap(ErrInvalid, "malformed cursor")
+	}
+
+	var decoded Cursor
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	if err = dec.Decode(&decoded); err != nil {
+		return nil, errors.Wrap(ErrInvalid, "malformed cursor")
+	}
+	if decoded.Sort != c.Sort || len(decoded.Values) != len(sorts) {
+		return nil, errors.Wrap(ErrInvalid, "cursor does not match the sort")
+	}
+	for _, i := range decoded.Times {
+		if i < 0 || i >= len(decoded.Values) {
+			return nil, errors.Wrap(ErrInvalid, "malformed cursor")
+		}
+		v, _ := decoded.Values[i].(string)
+		t, err := time.Parse(time.RFC3339Nano, v)
+		if err != nil {
+			return nil, errors.Wrap(ErrInvalid, "malformed cursor")
+		}
+		decoded.Values[i] = t
+	}
+	return &decoded, nil
+}
+
+// start reports whether c is the start of the list
+func (c *Cursor) start() bool {
+	return c == nil || len(c.Values) == 0
+}
+
+func sortString(sorts []Sort) string {
+	fields := make([]string, len(sorts))
+	for i, s := range sorts {
+		fields[i] = s.Field
+		if s.Desc {
+			fields[i] = "-" + s.Field
+		}
+	}
+	return strings.Join(fields, ",")
+}
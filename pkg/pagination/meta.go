@@ -0,0 +1,82 @@
+package pagination
+
+import (
+	"net/url"
+	"strconv"
+)
+
+// Result describes a fetched page, it is returned by the data layer
+type Result struct {
+	// Total is the number of items matching the filters
+	Total int64
+	// NextCursor is the cursor of the next page, empty on the last page
+	NextCursor string
+}
+
+// Meta is the standard metadata of list responses
+type Meta struct {
+	Page       int    `json:"page,omitempty"`
+	Limit      int    `json:"limit"`
+	Total      int64  `json:"total"`
+	NextCursor string `json:"next_cursor,omitempty"`
+	Links      Links  `json:"links"`
+}
+
+// Links are the URLs of the neighbouring pages
+type Links struct {
+	Self  string `json:"self"`
+	First string `json:"first,omitempty"`
+	Prev  string `json:"prev,omitempty"`
+	Next  string `json:"next,omitempty"`
+	Last  string `json:"last,omitempty"`
+}
+
+// Trim returns how many of the n fetched items belong to the page and, in
+// cursor mode, whether there are more items: Build fetches one extra item to tell.
+func (q Query) Trim(n int) (int, bool) {
+	if n > q.Limit {
+		return q.Limit, true
+	}
+	return n, false
+}
+
+// NewMeta returns the metadata of the page fetched with q, the links are
+// built from u, the URL of the request as the client sent it
+func NewMeta(u *url.URL, q Query, res Result) Meta {
+	m := Meta{
+		Page:       q.Page,
+		Limit:      q.Limit,
+		Total:      res.Total,
+		NextCursor: res.NextCursor,
+		Links:      Links{Self: u.RequestURI()},
+	}
+
+	if q.Cursor != nil {
+		m.Links.First = link(u, "cursor", "")
+		if res.NextCursor != "" {
+			m.Links.Next = link(u, "cursor", res.NextCursor)
+		}
+		return m
+	}
+
+	lastPage := int((res.Total + int64(q.Limit) - 1) / int64(q.Limit))
+	if lastPage < 1 {
+		lastPage = 1
+	}
+	m.Links.First = link(u, "page", "1")
+	m.Links.Last = link(u, "page", strconv.Itoa(lastPage))
+	if q.Page > 1 {
+		m.Links.Prev = link(u, "page", strconv.Itoa(q.Page-1))
+	}
+	if q.Page < lastPage {
+		m.Links.Next = link(u, "page", strconv.Itoa(q.Page+1))
+	}
+	return m
+}
+
+func link(u *url.URL, key, value string) string {
+	values := u.Query()
+	values.Set(key, value)
+	l := url.URL{Path: u.Path, RawQuery: values.Encode()}
+	return l.RequestURI()
+}
@@ -0,0 +1,197 @@
+// Package pagination parses paging, sorting and filtering parameters of list
+// requests into a Query, builds the matching SQL fragments and the response metadata.
+//
+// A list request looks like
+//
+//	GET /skeletons?page=2&limit=20&sort=-created_at,name&filter=name:like:bone&filter=id:in:1|2|3
+//
+// or, for cursor (keyset) paging, carries cursor instead of page, the cursor
+// of the next page being returned in the metadata.
+package pagination
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"go-skeleton-auth/pkg/errors"
+)
+
+const (
+	defaultLimit = 20
+	maxLimit     = 100
+)
+
+// ErrInvalid is the cause of every error returned by Parse
+var ErrInvalid = errors.New("invalid list query")
+
+// Operators of filter expressions
+const (
+	OpEq   = "eq"
+	OpNe   = "ne"
+	OpGt   = "gt"
+	OpGte  = "gte"
+	OpLt   = "lt"
+	OpLte  = "lte"
+	OpLike = "like"
+	OpIn   = "in"
+)
+
+// Query is a parsed list request
+type Query struct {
+	// Page is the 1-based page number, 0 in cursor mode
+	Page  int
+	Limit int
+	// Cursor is set in cursor mode
+	Cursor *Cursor
+	Sort   []Sort
+	Filter []Filter
+}
+
+// Sort orders the list by a field
+type Sort struct {
+	Field string
+	Desc  bool
+}
+
+// Filter is a field, an operator and its value.
+// Values of the in operator are separated by |.
+type Filter struct {
+	Field string
+	Op    string
+	Value string
+}
+
+// Options restrict the fields a list can be sorted and filtered by
+type Options struct {
+	// DefaultLimit applies when limit is absent, 20 when 0
+	DefaultLimit int
+	// MaxLimit caps limit, 100 when 0
+	MaxLimit int
+	// Sortable and Filterable are the API field names accepted in sort and filter
+	Sortable   []string
+	Filterable []string
+	// DefaultSort applies when sort is absent
+	DefaultSort []Sort
+	// Key is a unique field always appended to the sort, so that the order
+	// is total, which cursor paging requires
+	Key string
+}
+
+// Parse reads page, limit, cursor, sort and filter from the query string of r
+func Parse(r *http.Request, opts Options) (Query, error) {
+	var (
+		q      Query
+		err    error
+		values = r.URL.Query()
+	)
+
+	if opts.DefaultLimit == 0 {
+		opts.DefaultLimit = defaultLimit
+	}
+	if opts.MaxLimit == 0 {
+		opts.MaxLimit = maxLimit
+	}
+
+	q.Limit = opts.DefaultLimit
+	if v := values.Get("limit"); v != "" {
+		if q.Limit, err = strconv.Atoi(v); err != nil || q.Limit < 1 {
+			return Query{}, errors.Wrapf(ErrInvalid, "limit %q", v)
+		}
+		if q.Limit > opts.MaxLimit {
+			q.Limit = opts.MaxLimit
+		}
+	}
+
+	if q.Sort, err = parseSort(values.Get("sort"), opts); err != nil {
+		return Query{}, err
+	}
+
+	for _, v := range values["filter"] {
+		f, err := parseFilter(v, opts)
+		if err != nil {
+			return Query{}, err
+		}
+		q.Filter = append(q.Filter, f)
+	}
+
+	if _, ok := values["cursor"]; ok {
+		if values.Get("page") != "" {
+			return Query{}, errors.Wrap(ErrInvalid, "page and cursor are exclusive")
+		}
+		if q.Cursor, err = decodeCursor(values.Get("cursor"), q.Sort); err != nil {
+			return Query{}, err
+		}
+		return q, nil
+	}
+
+	q.Page = 1
+	if v := values.Get("page"); v != "" {
+		if q.Page, err = strconv.Atoi(v); err != nil || q.Page < 1 {
+			return Query{}, errors.Wrapf(ErrInvalid, "page %q", v)
+		}
+	}
+	return q, nil
+}
+
+// Offset is the number of items before the page
+func (q Query) Offset() int {
+	if q.Page < 1 {
+		return 0
+	}
+	return (q.Page - 1) * q.Limit
+}
+
+func parseSort(v string, opts Options) ([]Sort, error) {
+	var sorts []Sort
+	if v == "" {
+		sorts = append(sorts, opts.DefaultSort...)
+	}
+
+	for _, field := range strings.Split(v, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+		s := Sort{Field: strings.TrimPrefix(field, "-"), Desc: strings.HasPrefix(field, "-")}
+		if !contains(opts.Sortable, s.Field) {
+			return nil, errors.Wrapf(ErrInvalid, "cannot sort by %q", s.Field)
+		}
+		sorts = append(sorts, s)
+	}
+
+	if opts.Key != "" {
+		for _, s := range sorts {
+			if s.Field == opts.Key {
+				return sorts, nil
+			}
+		}
+		sorts = append(sorts, Sort{Field: opts.Key})
+	}
+	return sorts, nil
+}
+
+func parseFilter(v string, opts Options) (Filter, error) {
+	parts := strings.SplitN(v, ":", 3)
+	if len(parts) != 3 {
+		return Filter{}, errors.Wrapf(ErrInvalid, "filter %q, expected field:op:value", v)
+	}
+
+	f := Filter{Field: parts[0], Op: parts[1], Value: parts[2]}
+	if !contains(opts.Filterable, f.Field) {
+		return Filter{}, errors.Wrapf(ErrInvalid, "cannot filter by %q", f.Field)
+	}
+	if _, ok := operators[f.Op]; !ok {
+		return Filter{}, errors.Wrapf(ErrInvalid, "unknown filter operator %q", f.Op)
+	}
+	return f, nil
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,172 @@
+package pagination
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"go-skeleton-auth/pkg/errors"
+
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	testOptions = Options{
+		MaxLimit:    50,
+		Sortable:    []string{"id", "name", "created_at"},
+		Filterable:  []string{"id", "name"},
+		DefaultSort: []Sort{{Field: "created_at", Desc: true}},
+		Key:         "id",
+	}
+	testColumns = Columns{
+		"id":         "s.id",
+		"name":       "s.name",
+		"created_at": "s.created_at",
+	}
+)
+
+func parse(t *testing.T, target string) (Query, error) {
+	t.Helper()
+	return Parse(httptest.NewRequest("GET", target, nil), testOptions)
+}
+
+func TestParse(t *testing.T) {
+	q, err := parse(t, "/skeletons")
+	require.NoError(t, err)
+	require.Equal(t, Query{
+		Page:  1,
+		Limit: 20,
+		Sort:  []Sort{{Field: "created_at", Desc: true}, {Field: "id"}},
+	}, q)
+
+	q, err = parse(t, "/skeletons?page=3&limit=500&sort=name,-id&filter=name:like:bone%25&filter=id:in:1|2")
+	require.NoError(t, err)
+	require.Equal(t, Query{
+		Page:  3,
+		Limit: 50,
+		Sort:  []Sort{{Field: "name"}, {Field: "id", Desc: true}},
+		Filter: []Filter{
+			{Field: "name", Op: OpLike, Value: "bone%"},
+			{Field: "id", Op: OpIn, Value: "1|2"},
+		},
+	}, q)
+	require.Equal(t, 100, q.Offset())
+
+	for _, target := range []string{
+		"/skeletons?page=0",
+		"/skeletons?limit=abc",
+		"/skeletons?sort=password",
+		"/skeletons?filter=created_at:eq:1",
+		"/skeletons?filter=name:regexp:x",
+		"/skeletons?filter=name",
+		"/skeletons?page=2&cursor=",
+		"/skeletons?cursor=!!!",
+	} {
+		_, err := parse(t, target)
+		require.Error(t, err, target)
+		require.Equal(t, ErrInvalid, errors.Cause(err), target)
+	}
+}
+
+func TestBuildPage(t *testing.T) {
+	q, err := parse(t, "/skeletons?page=2&limit=10&sort=name&filter=name:like:b%25&filter=id:in:1|2|3")
+	require.NoError(t, err)
+
+	query, args, err := q.Build("SELECT * FROM skeletons s", testColumns)
+	require.NoError(t, err)
+	require.Equal(t, "SELECT * FROM skeletons s WHERE s.name LIKE ? AND s.id IN (?, ?, ?) ORDER BY s.name, s.id LIMIT ? OFFSET ?", query)
+	require.Equal(t, []interface{}{"b%", "1", "2", "3", 10, 10}, args)
+
+	_, _, err = q.Build("SELECT * FROM skeletons s", Columns{"id": "s.id"})
+	require.Error(t, err)
+}
+
+func TestBuildCursor(t *testing.T) {
+	q, err := parse(t, "/skeletons?cursor=&limit=2")
+	require.NoError(t, err)
+
+	query, args, err := q.Build("SELECT * FROM skeletons s", testColumns)
+	require.NoError(t, err)
+	require.Equal(t, "SELECT * FROM skeletons s ORDER BY s.created_at DESC, s.id LIMIT ?", query)
+	require.Equal(t, []interface{}{3}, args)
+
+	n, more := q.Trim(3)
+	require.Equal(t, 2, n)
+	require.True(t, more)
+
+	createdAt := time.Date(2021, 6, 1, 10, 0, 0, 0, time.UTC)
+	cursor := q.NextCursor(func(field string) interface{} {
+		if field == "id" {
+			return 42
+		}
+		return createdAt
+	})
+
+	q, err = parse(t, "/skeletons?limit=2&filter=name:eq:x&cursor="+cursor)
+	require.NoError(t, err)
+
+	query, args, err = q.Build("SELECT * FROM skeletons s", testColumns)
+	require.NoError(t, err)
+	require.Equal(t, "SELECT * FROM skeletons s WHERE s.name = ? AND ((s.created_at < ?) OR (s.created_at = ? AND s.id > ?)) ORDER BY s.created_at DESC, s.id LIMIT ?", query)
+	require.Len(t, args, 5)
+	require.True(t, createdAt.Equal(args[1].(time.Time)))
+	require.EqualValues(t, "42", args[3])
+
+	// the cursor belongs to the default sort
+	_, err = parse(t, "/skeletons?sort=name&cursor="+cursor)
+	require.Error(t, err)
+}
+
+func TestCursorTimeZone(t *testing.T) {
+	q, err := parse(t, "/skeletons?cursor=")
+	require.NoError(t, err)
+
+	// a DATETIME scanned with loc=Local in a zone ahead of UTC
+	createdAt := time.Date(2021, 6, 1, 10, 0, 0, 500, time.FixedZone("WIB", 7*60*60))
+	cursor := q.NextCursor(func(field string) interface{} {
+		if field == "id" {
+			return 42
+		}
+		return createdAt
+	})
+
+	q, err = parse(t, "/skeletons?cursor="+cursor)
+	require.NoError(t, err)
+	_, args, err := q.Build("SELECT * FROM skeletons s", testColumns)
+	require.NoError(t, err)
+
+	// bound as a time, the driver formats it in the connection location
+	bound, ok := args[0].(time.Time)
+	require.True(t, ok)
+	require.True(t, createdAt.Equal(bound))
+	require.Equal(t, "2021-06-01 10:00:00", bound.In(createdAt.Location()).Format("2006-01-02 15:04:05"))
+}
+
+func TestNewMeta(t *testing.T) {
+	r := httptest.NewRequest("GET", "/skeletons?page=2&limit=10&sort=name", nil)
+	q, err := Parse(r, testOptions)
+	require.NoError(t, err)
+
+	m := NewMeta(r.URL, q, Result{Total: 25})
+	require.Equal(t, Meta{
+		Page:  2,
+		Limit: 10,
+		Total: 25,
+		Links: Links{
+			Self:  "/skeletons?page=2&limit=10&sort=name",
+			First: "/skeletons?limit=10&page=1&sort=name",
+			Prev:  "/skeletons?limit=10&page=1&sort=name",
+			Next:  "/skeletons?limit=10&page=3&sort=name",
+			Last:  "/skeletons?limit=10&page=3&sort=name",
+		},
+	}, m)
+
+	r = httptest.NewRequest("GET", "/skeletons?cursor=", nil)
+	q, err = Parse(r, testOptions)
+	require.NoError(t, err)
+
+	m = NewMeta(r.URL, q, Result{Total: 25, NextCursor: "abc"})
+	require.Equal(t, "abc", m.NextCursor)
+	require.Equal(t, "/skeletons?cursor=abc", m.Links.Next)
+	require.Empty(t, m.Links.Last)
+}
@@ -0,0 +1,157 @@
+package pagination
+
+import (
+	"strings"
+
+	"go-skeleton-auth/pkg/errors"
+)
+
+// Columns maps API field names to SQL column expressions.
+// Only the columns are written into the SQL, values are always bound
+// as ? placeholders, so a Query cannot inject SQL.
+type Columns map[string]string
+
+var operators = map[string]string{
+	OpEq:   "=",
+	OpNe:   "<>",
+	OpGt:   ">",
+	OpGte:  ">=",
+	OpLt:   "<",
+	OpLte:  "<=",
+	OpLike: "LIKE",
+	OpIn:   "IN",
+}
+
+// Build appends the WHERE, ORDER BY and LIMIT clauses of q to base, e.g.
+//
+//	query, args, err := q.Build("SELECT skeleton_id, skeleton_name FROM skeletons", columns)
+//	err = d.db.SelectContext(ctx, &rows, d.db.Rebind(query), args...)
+//
+// In cursor mode one more item than the limit is fetched, see Query.Trim.
+func (q Query) Build(base string, columns Columns) (string, []interface{}, error) {
+	where, args, err := q.Where(columns)
+	if err != nil {
+		return "", nil, err
+	}
+	orderBy, err := q.OrderBy(columns)
+	if err != nil {
+		return "", nil, err
+	}
+	limit, limitArgs := q.LimitClause()
+
+	query := strings.Join(nonEmpty(base, where, orderBy, limit), " ")
+	return query, append(args, limitArgs...), nil
+}
+
+// Where returns the WHERE clause of the filters and the cursor, empty when there is none
+func (q Query) Where(columns Columns) (string, []interface{}, error) {
+	var (
+		conds []string
+		args  []interface{}
+	)
+
+	for _, f := range q.Filter {
+		col, ok := columns[f.Field]
+		if !ok {
+			return "", nil, errors.Errorf("pagination: no column for filter field %q", f.Field)
+		}
+
+		if f.Op == OpIn {
+			values := strings.Split(f.Value, "|")
+			conds = append(conds, col+" IN ("+placeholders(len(values))+")")
+			for _, v := range values {
+				args = append(args, v)
+			}
+			continue
+		}
+		conds = append(conds, col+" "+operators[f.Op]+" ?")
+		args = append(args, f.Value)
+	}
+
+	if !q.Cursor.start() {
+		cond, cursorArgs, err := q.after(columns)
+		if err != nil {
+			return "", nil, err
+		}
+		conds = append(conds, cond)
+		args = append(args, cursorArgs...)
+	}
+
+	if len(conds) == 0 {
+		return "", nil, nil
+	}
+	return "WHERE " + strings.Join(conds, " AND "), args, nil
+}
+
+// OrderBy returns the ORDER BY clause, empty when there is no sort
+func (q Query) OrderBy(columns Columns) (string, error) {
+	var terms []string
+	for _, s := range q.Sort {
+		col, ok := columns[s.Field]
+		if !ok {
+			return "", errors.Errorf("pagination: no column for sort field %q", s.Field)
+		}
+		if s.Desc {
+			col += " DESC"
+		}
+		terms = append(terms, col)
+	}
+
+	if len(terms) == 0 {
+		return "", nil
+	}
+	return "ORDER BY " + strings.Join(terms, ", "), nil
+}
+
+// LimitClause returns the LIMIT clause of the page
+func (q Query) LimitClause() (string, []interface{}) {
+	if q.Cursor != nil {
+		return "LIMIT ?", []interface{}{q.Limit + 1}
+	}
+	return "LIMIT ? OFFSET ?", []interface{}{q.Limit, q.Offset()}
+}
+
+// after returns the keyset condition selecting the items after the cursor:
+// (a > ?) OR (a = ? AND b > ?) ..., with < for descending fields
+func (q Query) after(columns Columns) (string, []interface{}, error) {
+	var (
+		ors  []string
+		args []interface{}
+	)
+
+	for i, s := range q.Sort {
+		var ands []string
+		for j := 0; j <= i; j++ {
+			col, ok := columns[q.Sort[j].Field]
+			if !ok {
+				return "", nil, errors.Errorf("pagination: no column for sort field %q", q.Sort[j].Field)
+			}
+
+			op := "="
+			if j == i {
+				op = ">"
+				if s.Desc {
+					op = "<"
+				}
+			}
+			ands = append(ands, col+" "+op+" ?")
+			args = append(args, q.Cursor.Values[j])
+		}
+		ors = append(ors, "("+strings.Join(ands, " AND ")+")")
+	}
+	return "(" + strings.Join(ors, " OR ") + ")", args, nil
+}
+
+func placeholders(n int) string {
+	return strings.TrimSuffix(strings.Repeat("?, ", n), ", ")
+}
+
+func nonEmpty(parts ...string) []string {
+	var out []string
+	for _, p := range parts {
+		if p != "" {
+			out = append(out, p)
+		}
+	}
+	return out
+}
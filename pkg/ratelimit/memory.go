@@ -0,0 +1,52 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// sweepEvery is the number of takes between two sweeps of the full buckets
+const sweepEvery = 10000
+
+// MemoryStore keeps the buckets of one replica in memory
+type MemoryStore struct {
+	mu      sync.Mutex
+	buckets map[string]bucket
+	takes   int
+}
+
+type bucket struct {
+	state State
+	// full is when the bucket is full again and can be forgotten
+	full time.Time
+}
+
+// NewMemoryStore ...
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{buckets: make(map[string]bucket)}
+}
+
+// Take implements Store
+func (m *MemoryStore) Take(ctx context.Context, key string, l Limit, now time.Time) (Result, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.takes++
+	if m.takes%sweepEvery == 0 {
+		m.sweep(now)
+	}
+
+	state, res := Take(m.buckets[key].state, l, now)
+	m.buckets[key] = bucket{state: state, full: now.Add(res.Reset)}
+	return res, nil
+}
+
+// sweep forgets the full buckets, they are equivalent to missing ones
+func (m *MemoryStore) sweep(now time.Time) {
+	for key, b := range m.buckets {
+		if !now.Before(b.full) {
+			delete(m.buckets, key)
+		}
+	}
+}
@@ -0,0 +1,130 @@
+// Package ratelimit limits requests with token buckets, per key and per route.
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Limit allows Requests per Per period, in bursts of up to Burst requests
+type Limit struct {
+	Requests int
+	Per      time.Duration
+	// Burst is the capacity of the bucket, Requests when 0
+	Burst int
+}
+
+// Unlimited reports whether the limit lets everything through
+func (l Limit) Unlimited() bool {
+	return l.Requests <= 0 || l.Per <= 0
+}
+
+func (l Limit) capacity() float64 {
+	if l.Burst > 0 {
+		return float64(l.Burst)
+	}
+	return float64(l.Requests)
+}
+
+// rate is the number of tokens added per second
+func (l Limit) rate() float64 {
+	return float64(l.Requests) / l.Per.Seconds()
+}
+
+// Result is the outcome of taking a token
+type Result struct {
+	Allowed bool
+	// Limit is the capacity of the bucket
+	Limit     int
+	Remaining int
+	// RetryAfter is how long to wait for a token, 0 when allowed
+	RetryAfter time.Duration
+	// Reset is how long until the bucket is full again
+	Reset time.Duration
+}
+
+// State is the state of a bucket
+type State struct {
+	Tokens float64
+	Last   time.Time
+}
+
+// Take refills the bucket of state for the time elapsed since its last use and
+// takes a token from it. A zero state is a full bucket.
+// Shared stores apply it atomically, e.g. in a transaction or a script.
+func Take(state State, l Limit, now time.Time) (State, Result) {
+	capacity, rate := l.capacity(), l.rate()
+
+	tokens := capacity
+	if !state.Last.IsZero() {
+		tokens = math.Min(capacity, state.Tokens+now.Sub(state.Last).Seconds()*rate)
+	}
+
+	res := Result{Limit: int(capacity)}
+	if tokens >= 1 {
+		tokens--
+		res.Allowed = true
+	} else {
+		res.RetryAfter = seconds((1 - tokens) / rate)
+	}
+	res.Remaining = int(math.Floor(tokens))
+	res.Reset = seconds((capacity - tokens) / rate)
+
+	return State{Tokens: tokens, Last: now}, res
+}
+
+func seconds(s float64) time.Duration {
+	return time.Duration(s * float64(time.Second))
+}
+
+// Store keeps the buckets. The in-memory store limits each replica on its own,
+// a store shared by the replicas, e.g. backed by Redis, limits them together.
+type Store interface {
+	// Take takes a token from the bucket of key, see the Take function
+	Take(ctx context.Context, key string, l Limit, now time.Time) (Result, error)
+}
+
+// Route overrides the default limit for the paths starting with PathPrefix
+type Route struct {
+	PathPrefix string
+	Limit      Limit
+}
+
+// Limiter applies the limit of the longest matching route prefix, or the default one.
+// Each route prefix has its own buckets.
+type Limiter struct {
+	store  Store
+	def    Limit
+	routes []Route
+	now    func() time.Time
+}
+
+// New ...
+func New(store Store, def Limit, routes ...Route) *Limiter {
+	routes = append([]Route{}, routes...)
+	sort.SliceStable(routes, func(i, j int) bool {
+		return len(routes[i].PathPrefix) > len(routes[j].PathPrefix)
+	})
+	return &Limiter{store: store, def: def, routes: routes, now: time.Now}
+}
+
+// Take takes a token for key, e.g. a principal or a client IP, on path.
+// ok is false when path is not limited.
+func (l *Limiter) Take(ctx context.Context, path, key string) (res Result, ok bool, err error) {
+	limit, bucket := l.def, ""
+	for _, r := range l.routes {
+		if strings.HasPrefix(path, r.PathPrefix) {
+			limit, bucket = r.Limit, r.PathPrefix
+			break
+		}
+	}
+	if limit.Unlimited() {
+		return Result{}, false, nil
+	}
+
+	res, err = l.store.Take(ctx, bucket+"\x00"+key, limit, l.now())
+	return res, err == nil, err
+}
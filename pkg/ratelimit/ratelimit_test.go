@@ -0,0 +1,72 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTake(t *testing.T) {
+	l := Limit{Requests: 60, Per: time.Minute, Burst: 2}
+	now := time.Unix(1600000000, 0)
+
+	state, res := Take(State{}, l, now)
+	require.True(t, res.Allowed)
+	require.Equal(t, 2, res.Limit)
+	require.Equal(t, 1, res.Remaining)
+	require.Equal(t, time.Second, res.Reset)
+
+	state, res = Take(state, l, now)
+	require.True(t, res.Allowed)
+	require.Equal(t, 0, res.Remaining)
+
+	state, res = Take(state, l, now.Add(500*time.Millisecond))
+	require.False(t, res.Allowed)
+	require.Equal(t, 500*time.Millisecond, res.RetryAfter)
+
+	_, res = Take(state, l, now.Add(time.Second))
+	require.True(t, res.Allowed)
+}
+
+func TestLimiter(t *testing.T) {
+	ctx := context.Background()
+	l := New(NewMemoryStore(), Limit{Requests: 1, Per: time.Minute},
+		Route{PathPrefix: "/example/admin", Limit: Limit{Requests: 2, Per: time.Minute}},
+		Route{PathPrefix: "/example/public", Limit: Limit{}},
+	)
+	now := time.Unix(1600000000, 0)
+	l.now = func() time.Time { return now }
+
+	res, ok, err := l.Take(ctx, "/example/skeleton", "a")
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.True(t, res.Allowed)
+
+	res, _, _ = l.Take(ctx, "/example/skeleton", "a")
+	require.False(t, res.Allowed)
+	require.Equal(t, time.Minute, res.RetryAfter)
+
+	// keys and routes have their own buckets
+	res, _, _ = l.Take(ctx, "/example/skeleton", "b")
+	require.True(t, res.Allowed)
+	res, _, _ = l.Take(ctx, "/example/admin/tokens/revoke", "a")
+	require.True(t, res.Allowed)
+	require.Equal(t, 2, res.Limit)
+
+	_, ok, err = l.Take(ctx, "/example/public/x", "a")
+	require.NoError(t, err)
+	require.False(t, ok)
+}
+
+func TestMemoryStoreSweep(t *testing.T) {
+	m := NewMemoryStore()
+	l := Limit{Requests: 1, Per: time.Second}
+	now := time.Unix(1600000000, 0)
+
+	_, err := m.Take(context.Background(), "a", l, now)
+	require.NoError(t, err)
+	m.sweep(now.Add(time.Second))
+	require.Empty(t, m.buckets)
+}
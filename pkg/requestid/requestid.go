@@ -0,0 +1,64 @@
+// Package requestid correlates the logs, traces and outbound calls of a request
+// with the X-Request-ID header.
+package requestid
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/rs/xid"
+)
+
+// Header is the request and response header carrying the request ID
+const Header = "X-Request-ID"
+
+// maxLength bounds the length of request IDs accepted from clients
+const maxLength = 128
+
+type contextKey struct{}
+
+// NewContext returns a copy of ctx carrying id
+func NewContext(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, contextKey{}, id)
+}
+
+// FromContext returns the request ID of ctx, empty when there is none
+func FromContext(ctx context.Context) string {
+	id, _ := ctx.Value(contextKey{}).(string)
+	return id
+}
+
+// Middleware accepts the X-Request-ID of the request, or generates one when it
+// is missing or invalid, stores it in the request context and echoes it in the response
+func Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(Header)
+		if !valid(id) {
+			id = xid.New().String()
+		}
+
+		w.Header().Set(Header, id)
+		next.ServeHTTP(w, r.WithContext(NewContext(r.Context(), id)))
+	})
+}
+
+// Inject sets the request ID of ctx on outbound request headers, unless already set
+func Inject(ctx context.Context, h http.Header) {
+	if id := FromContext(ctx); id != "" && h.Get(Header) == "" {
+		h.Set(Header, id)
+	}
+}
+
+// valid accepts printable ASCII IDs of a sane length, so that client input
+// cannot forge log lines or bloat them
+func valid(id string) bool {
+	if id == "" || len(id) > maxLength {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] < 0x21 || id[i] > 0x7e {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,74 @@
+package requestid_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go-skeleton-auth/pkg/httpclient"
+	"go-skeleton-auth/pkg/requestid"
+
+	"github.com/opentracing/opentracing-go"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMiddleware(t *testing.T) {
+	testCases := []struct {
+		name     string
+		incoming string
+		keep     bool
+	}{
+		{"generated", "", false},
+		{"accepted", "abc-123", true},
+		{"too long", strings.Repeat("a", 129), false},
+		{"control characters", "abc\nforged log line", false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var seen string
+			h := requestid.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				seen = requestid.FromContext(r.Context())
+			}))
+
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tc.incoming != "" {
+				r.Header.Set(requestid.Header, tc.incoming)
+			}
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+
+			require.NotEmpty(t, seen)
+			require.Equal(t, seen, w.Header().Get(requestid.Header))
+			if tc.keep {
+				require.Equal(t, tc.incoming, seen)
+			} else {
+				require.NotEqual(t, tc.incoming, seen)
+			}
+		})
+	}
+}
+
+func TestForwarded(t *testing.T) {
+	var forwarded string
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		forwarded = r.Header.Get(requestid.Header)
+	}))
+	defer upstream.Close()
+
+	client := httpclient.NewClient(opentracing.NoopTracer{})
+	ctx := requestid.NewContext(context.Background(), "req-1")
+
+	resp, err := client.Get(ctx, upstream.URL, "upstream", nil)
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, "req-1", forwarded)
+
+	// an explicit header wins
+	resp, err = client.Get(ctx, upstream.URL, "upstream", http.Header{requestid.Header: {"explicit"}})
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, "explicit", forwarded)
+}
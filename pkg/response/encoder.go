@@ -0,0 +1,195 @@
+package response
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/vmihailenco/msgpack/v5"
+	"google.golang.org/protobuf/proto"
+)
+
+// Media types of the default encoders
+const (
+	ContentTypeJSON     = "application/json"
+	ContentTypeMsgPack  = "application/msgpack"
+	ContentTypeProtobuf = "application/x-protobuf"
+	ContentTypeCSV      = "text/csv"
+)
+
+// ErrUnsupported is returned by an Encoder that cannot encode a response,
+// e.g. the CSV encoder for a response whose data is not a list
+var ErrUnsupported = errors.New("response: unsupported by encoder")
+
+// Encoder encodes a response in one media type
+type Encoder interface {
+	// ContentType is the media type matched against the Accept header
+	ContentType() string
+	// Encode returns the body, or ErrUnsupported when res cannot be encoded
+	Encode(res *Response) ([]byte, error)
+}
+
+var (
+	encodersMu sync.RWMutex
+	// encoders in order of preference, the first one is the default
+	encoders = []Encoder{
+		jsonEncoder{},
+		msgpackEncoder{},
+		protobufEncoder{},
+		csvEncoder{},
+	}
+)
+
+// RegisterEncoder adds an encoder, replacing the one with the same content type.
+// New encoders are preferred last when the client accepts several media types equally.
+func RegisterEncoder(e Encoder) {
+	encodersMu.Lock()
+	defer encodersMu.Unlock()
+
+	for i, registered := range encoders {
+		if registered.ContentType() == e.ContentType() {
+			encoders[i] = e
+			return
+		}
+	}
+	encoders = append(encoders, e)
+}
+
+func registeredEncoders() []Encoder {
+	encodersMu.RLock()
+	defer encodersMu.RUnlock()
+	return append([]Encoder{}, encoders...)
+}
+
+// jsonEncoder encodes the standard envelope
+type jsonEncoder struct{}
+
+func (jsonEncoder) ContentType() string { return ContentTypeJSON }
+
+func (jsonEncoder) Encode(res *Response) ([]byte, error) {
+	return json.Marshal(res)
+}
+
+// msgpackEncoder encodes the standard envelope with the JSON field names
+type msgpackEncoder struct{}
+
+func (msgpackEncoder) ContentType() string { return ContentTypeMsgPack }
+
+func (msgpackEncoder) Encode(res *Response) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := msgpack.NewEncoder(&buf)
+	enc.SetCustomStructTag("json")
+	if err := enc.Encode(res); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// protobufEncoder encodes the data of the response when it is a protobuf message.
+// The envelope has no protobuf schema, so errors are not encoded.
+type protobufEncoder struct{}
+
+func (protobufEncoder) ContentType() string { return ContentTypeProtobuf }
+
+func (protobufEncoder) Encode(res *Response) ([]byte, error) {
+	msg, ok := res.Data.(proto.Message)
+	if !ok || res.Error.Status {
+		return nil, ErrUnsupported
+	}
+	return proto.Marshal(msg)
+}
+
+// csvEncoder encodes the data of list responses, one row per element.
+// Data must be a [][]string or a slice of structs, whose columns are named
+// by the csv tag, the json tag or the field name, in that order.
+type csvEncoder struct{}
+
+func (csvEncoder) ContentType() string { return ContentTypeCSV }
+
+func (csvEncoder) Encode(res *Response) ([]byte, error) {
+	if res.Error.Status {
+		return nil, ErrUnsupported
+	}
+
+	var records [][]string
+	switch data := res.Data.(type) {
+	case [][]string:
+		records = data
+	default:
+		var ok bool
+		if records, ok = structRecords(res.Data); !ok {
+			return nil, ErrUnsupported
+		}
+	}
+
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	if err := w.WriteAll(records); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// structRecords converts a slice of structs into a header and one record per element
+func structRecords(data interface{}) ([][]string, bool) {
+	v := reflect.ValueOf(data)
+	if v.Kind() != reflect.Slice {
+		return nil, false
+	}
+
+	elem := v.Type().Elem()
+	if elem.Kind() == reflect.Ptr {
+		elem = elem.Elem()
+	}
+	if elem.Kind() != reflect.Struct {
+		return nil, false
+	}
+
+	var (
+		header  []string
+		columns []int
+	)
+	for i := 0; i < elem.NumField(); i++ {
+		f := elem.Field(i)
+		if f.PkgPath != "" {
+			continue
+		}
+		name := columnName(f)
+		if name == "-" {
+			continue
+		}
+		header = append(header, name)
+		columns = append(columns, i)
+	}
+
+	records := [][]string{header}
+	for i := 0; i < v.Len(); i++ {
+		row := v.Index(i)
+		if row.Kind() == reflect.Ptr {
+			if row.IsNil() {
+				continue
+			}
+			row = row.Elem()
+		}
+		record := make([]string, len(columns))
+		for j, col := range columns {
+			record[j] = fmt.Sprint(row.Field(col).Interface())
+		}
+		records = append(records, record)
+	}
+	return records, true
+}
+
+func columnName(f reflect.StructField) string {
+	for _, key := range []string{"csv", "json"} {
+		if tag := strings.Split(f.Tag.Get(key), ",")[0]; tag != "" {
+			return tag
+		}
+	}
+	return f.Name
+}
@@ -0,0 +1,122 @@
+package response
+
+import (
+	"context"
+	"encoding/json"
+	"mime"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// ContentTypeProblem is the media type of RFC 7807 problem details
+const ContentTypeProblem = "application/problem+json"
+
+// ProblemTypeBase is the URI prefix of the problem type, the application
+// error code is appended to it. When empty, the type is "about:blank".
+var ProblemTypeBase = ""
+
+// Problem is an RFC 7807 problem details object
+type Problem struct {
+	Type     string `json:"type"`
+	Title    string `json:"title"`
+	Status   int    `json:"status"`
+	Detail   string `json:"detail,omitempty"`
+	Instance string `json:"instance,omitempty"`
+	// Extensions are additional members, they are rendered next to the standard ones
+	Extensions map[string]interface{} `json:"-"`
+}
+
+// MarshalJSON flattens the extension members into the problem object
+func (p Problem) MarshalJSON() ([]byte, error) {
+	m := make(map[string]interface{}, len(p.Extensions)+5)
+	for k, v := range p.Extensions {
+		m[k] = v
+	}
+	m["type"] = p.Type
+	m["title"] = p.Title
+	m["status"] = p.Status
+	if p.Detail != "" {
+		m["detail"] = p.Detail
+	}
+	if p.Instance != "" {
+		m["instance"] = p.Instance
+	}
+	return json.Marshal(m)
+}
+
+// Problem returns the error of the response as problem details.
+// The application error code is kept in the "code" extension.
+func (res *Response) Problem(r *http.Request) Problem {
+	status := res.StatusCode
+	if status == 0 {
+		status = http.StatusInternalServerError
+	}
+
+	p := Problem{
+		Type:       "about:blank",
+		Title:      http.StatusText(status),
+		Status:     status,
+		Detail:     res.Error.Msg,
+		Extensions: map[string]interface{}{"code": res.Error.Code},
+	}
+	if len(res.Error.Fields) > 0 {
+		p.Extensions["errors"] = res.Error.Fields
+	}
+	if ProblemTypeBase != "" && res.Error.Code != 0 {
+		p.Type = ProblemTypeBase + strconv.Itoa(res.Error.Code)
+	}
+	if r != nil {
+		p.Instance = OriginalURL(r).RequestURI()
+	}
+	return p
+}
+
+type (
+	problemKey     struct{}
+	originalURLKey struct{}
+)
+
+// WithOriginalURL records u as the URL the client requested, for middlewares
+// rewriting r.URL, e.g. to strip a version segment
+func WithOriginalURL(ctx context.Context, u *url.URL) context.Context {
+	return context.WithValue(ctx, originalURLKey{}, u)
+}
+
+// OriginalURL returns the URL recorded with WithOriginalURL, r.URL when there is none
+func OriginalURL(r *http.Request) *url.URL {
+	if u, ok := r.Context().Value(originalURLKey{}).(*url.URL); ok {
+		return u
+	}
+	return r.URL
+}
+
+// ProblemDetails is a middleware selecting problem details for the error
+// responses of the routes it wraps, the legacy envelope is used otherwise
+func ProblemDetails(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), problemKey{}, true)))
+	})
+}
+
+// wantsProblem reports whether the error response to r is rendered as
+// problem details: either the route opted in with ProblemDetails or the
+// client asked for application/problem+json
+func wantsProblem(r *http.Request) bool {
+	if r == nil {
+		return false
+	}
+	if on, _ := r.Context().Value(problemKey{}).(bool); on {
+		return true
+	}
+	for _, accept := range r.Header.Values("Accept") {
+		for _, part := range strings.Split(accept, ",") {
+			mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+			if err == nil && mediaType == ContentTypeProblem && params["q"] != "0" {
+				return true
+			}
+		}
+	}
+	return false
+}
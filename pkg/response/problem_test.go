@@ -0,0 +1,86 @@
+package response
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestProblemDetails(t *testing.T) {
+	failing := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		resp := Response{}
+		resp.SetError(fmt.Errorf("skeleton not found"), http.StatusNotFound)
+		resp.Error.Code = 10004
+		resp.RenderJSON(w, r)
+	})
+	succeeding := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		resp := Response{Data: "ok"}
+		resp.RenderJSON(w, r)
+	})
+
+	testCases := []struct {
+		name        string
+		handler     http.Handler
+		accept      string
+		contentType string
+	}{
+		{"legacy envelope by default", failing, "", "application/json"},
+		{"negotiated", failing, "application/problem+json, application/json;q=0.5", ContentTypeProblem},
+		{"refused by quality", failing, "application/problem+json;q=0", "application/json"},
+		{"selected per route", ProblemDetails(failing), "application/json", ContentTypeProblem},
+		{"success keeps envelope", ProblemDetails(succeeding), ContentTypeProblem, "application/json"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/v1/skeletons/1?x=y", nil)
+			if tc.accept != "" {
+				r.Header.Set("Accept", tc.accept)
+			}
+			w := httptest.NewRecorder()
+			tc.handler.ServeHTTP(w, r)
+
+			require.Equal(t, tc.contentType, w.Header().Get("Content-Type"))
+			if tc.contentType != ContentTypeProblem {
+				return
+			}
+
+			require.Equal(t, http.StatusNotFound, w.Code)
+			var body map[string]interface{}
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+			require.Equal(t, map[string]interface{}{
+				"type":     "about:blank",
+				"title":    "Not Found",
+				"status":   float64(http.StatusNotFound),
+				"detail":   "skeleton not found",
+				"instance": "/v1/skeletons/1?x=y",
+				"code":     float64(10004),
+			}, body)
+		})
+	}
+}
+
+func TestProblemType(t *testing.T) {
+	defer func(base string) { ProblemTypeBase = base }(ProblemTypeBase)
+	ProblemTypeBase = "https://example.com/problems/"
+
+	resp := Response{StatusCode: http.StatusForbidden, Error: Error{Status: true, Code: 40301}}
+	require.Equal(t, "https://example.com/problems/40301", resp.Problem(nil).Type)
+}
+
+func TestProblemInstance(t *testing.T) {
+	resp := Response{StatusCode: http.StatusNotFound, Error: Error{Status: true, Code: 10004}}
+
+	r := httptest.NewRequest(http.MethodGet, "/v1/skeletons/1?x=y", nil)
+	// as seen by the handlers once the version segment is stripped
+	stripped := *r.URL
+	stripped.Path = "/skeletons/1"
+	r = r.WithContext(WithOriginalURL(r.Context(), r.URL))
+	r.URL = &stripped
+
+	require.Equal(t, "/v1/skeletons/1?x=y", resp.Problem(r).Instance)
+}
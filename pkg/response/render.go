@@ -0,0 +1,128 @@
+package response
+
+import (
+	"errors"
+	"mime"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Render writes the http response to the client in the media type preferred by
+// the Accept header among the registered encoders, JSON when there is none.
+// A successful response that no acceptable encoder can encode is replaced by
+// 406 Not Acceptable, an error response then falls back to JSON.
+// Errors are written as problem details like with RenderJSON.
+func (res *Response) Render(w http.ResponseWriter, r *http.Request) {
+	if res.StatusCode == 0 {
+		res.StatusCode = http.StatusOK
+	}
+
+	if res.Error.Status && wantsProblem(r) {
+		res.RenderJSON(w, r)
+		return
+	}
+
+	var accept []string
+	if r != nil {
+		accept = r.Header.Values("Accept")
+	}
+	for _, e := range negotiate(accept, registeredEncoders()) {
+		d, err := e.Encode(res)
+		if errors.Is(err, ErrUnsupported) {
+			continue
+		}
+		if err != nil {
+			res = &Response{
+				StatusCode: http.StatusInternalServerError,
+				Error:      Error{Status: true, Msg: err.Error()},
+			}
+			res.RenderJSON(w, r)
+			return
+		}
+
+		w.Header().Set("Content-Type", e.ContentType())
+		w.Header().Add("Vary", "Accept")
+		w.WriteHeader(res.StatusCode)
+		w.Write(d)
+		return
+	}
+
+	if !res.Error.Status {
+		res = &Response{
+			StatusCode: http.StatusNotAcceptable,
+			Error: Error{
+				Status: true,
+				Msg:    http.StatusText(http.StatusNotAcceptable),
+				Code:   http.StatusNotAcceptable,
+			},
+		}
+	}
+	w.Header().Add("Vary", "Accept")
+	res.RenderJSON(w, r)
+}
+
+type mediaRange struct {
+	mediaType string
+	q         float64
+}
+
+// negotiate returns the encoders acceptable for the Accept header values,
+// most preferred first
+func negotiate(accept []string, encoders []Encoder) []Encoder {
+	if len(accept) == 0 {
+		return encoders
+	}
+
+	var ranges []mediaRange
+	refused := make(map[string]bool)
+	for _, value := range accept {
+		for _, part := range strings.Split(value, ",") {
+			mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+			if err != nil {
+				continue
+			}
+			q := 1.0
+			if v, ok := params["q"]; ok {
+				if q, err = strconv.ParseFloat(v, 64); err != nil {
+					continue
+				}
+			}
+			if q <= 0 {
+				refused[mediaType] = true
+				continue
+			}
+			ranges = append(ranges, mediaRange{mediaType, q})
+		}
+	}
+	sort.SliceStable(ranges, func(i, j int) bool {
+		return ranges[i].q > ranges[j].q
+	})
+
+	var (
+		acceptable []Encoder
+		seen       = make(map[string]bool)
+	)
+	for _, mr := range ranges {
+		for _, e := range encoders {
+			ct := e.ContentType()
+			if seen[ct] || refused[ct] || !matches(mr.mediaType, ct) {
+				continue
+			}
+			seen[ct] = true
+			acceptable = append(acceptable, e)
+		}
+	}
+	return acceptable
+}
+
+func matches(mediaRange, contentType string) bool {
+	if mediaRange == "*/*" || mediaRange == contentType {
+		return true
+	}
+	if strings.HasSuffix(mediaRange, "/*") {
+		return strings.HasPrefix(contentType, strings.TrimSuffix(mediaRange, "*"))
+	}
+	return false
+}
@@ -0,0 +1,116 @@
+package response
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/vmihailenco/msgpack/v5"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+type skeleton struct {
+	ID     int    `json:"id"`
+	Name   string `json:"name" csv:"skeleton_name"`
+	secret string
+	Hidden string `csv:"-"`
+}
+
+func TestRender(t *testing.T) {
+	list := Response{Data: []skeleton{{ID: 1, Name: "a"}, {ID: 2, Name: "b,c"}}}
+	single := Response{Data: skeleton{ID: 1, Name: "a"}}
+	message := Response{Data: wrapperspb.String("hello")}
+	failed := Response{}
+	failed.SetError(fmt.Errorf("boom"), http.StatusBadRequest)
+
+	testCases := []struct {
+		name        string
+		res         Response
+		accept      string
+		status      int
+		contentType string
+	}{
+		{"default JSON", single, "", http.StatusOK, ContentTypeJSON},
+		{"wildcard", single, "*/*", http.StatusOK, ContentTypeJSON},
+		{"quality order", single, "application/json;q=0.5, application/msgpack", http.StatusOK, ContentTypeMsgPack},
+		{"type wildcard", list, "text/*", http.StatusOK, ContentTypeCSV},
+		{"CSV for lists", list, "text/csv", http.StatusOK, ContentTypeCSV},
+		{"CSV not for single items", single, "text/csv", http.StatusNotAcceptable, ContentTypeJSON},
+		{"CSV falls back", single, "text/csv, application/json;q=0.1", http.StatusOK, ContentTypeJSON},
+		{"refused type", single, "*/*, application/json;q=0", http.StatusOK, ContentTypeMsgPack},
+		{"protobuf message", message, "application/x-protobuf", http.StatusOK, ContentTypeProtobuf},
+		{"protobuf needs a message", single, "application/x-protobuf", http.StatusNotAcceptable, ContentTypeJSON},
+		{"not acceptable", single, "image/png", http.StatusNotAcceptable, ContentTypeJSON},
+		{"errors fall back to JSON", failed, "text/csv", http.StatusBadRequest, ContentTypeJSON},
+		{"errors as problem", failed, ContentTypeProblem, http.StatusBadRequest, ContentTypeProblem},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tc.accept != "" {
+				r.Header.Set("Accept", tc.accept)
+			}
+			w := httptest.NewRecorder()
+			res := tc.res
+			res.Render(w, r)
+
+			require.Equal(t, tc.status, w.Code)
+			require.Equal(t, tc.contentType, w.Header().Get("Content-Type"))
+		})
+	}
+}
+
+func TestRenderBodies(t *testing.T) {
+	render := func(res Response, accept string) []byte {
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		r.Header.Set("Accept", accept)
+		w := httptest.NewRecorder()
+		res.Render(w, r)
+		return w.Body.Bytes()
+	}
+
+	list := Response{Data: []*skeleton{{ID: 1, Name: "a"}, nil, {ID: 2, Name: "b,c"}}}
+	require.Equal(t, "id,skeleton_name\n1,a\n2,\"b,c\"\n", string(render(list, ContentTypeCSV)))
+
+	var decoded map[string]interface{}
+	dec := msgpack.NewDecoder(bytes.NewReader(render(Response{Data: "x"}, ContentTypeMsgPack)))
+	require.NoError(t, dec.Decode(&decoded))
+	require.Equal(t, "x", decoded["data"])
+	require.Contains(t, decoded, "error")
+
+	var msg wrapperspb.StringValue
+	require.NoError(t, proto.Unmarshal(render(Response{Data: wrapperspb.String("hi")}, ContentTypeProtobuf), &msg))
+	require.Equal(t, "hi", msg.Value)
+
+	var notAcceptable Response
+	require.NoError(t, json.Unmarshal(render(Response{Data: "x"}, "image/png"), &notAcceptable))
+	require.Equal(t, Error{Status: true, Msg: "Not Acceptable", Code: http.StatusNotAcceptable}, notAcceptable.Error)
+}
+
+type yamlEncoder struct{}
+
+func (yamlEncoder) ContentType() string { return "application/yaml" }
+
+func (yamlEncoder) Encode(res *Response) ([]byte, error) {
+	return []byte(fmt.Sprintf("data: %v\n", res.Data)), nil
+}
+
+func TestRegisterEncoder(t *testing.T) {
+	defer func(saved []Encoder) { encoders = saved }(registeredEncoders())
+	RegisterEncoder(yamlEncoder{})
+
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set("Accept", "application/yaml")
+	w := httptest.NewRecorder()
+	res := Response{Data: "x"}
+	res.Render(w, r)
+
+	require.Equal(t, "application/yaml", w.Header().Get("Content-Type"))
+	require.Equal(t, "data: x\n", w.Body.String())
+}
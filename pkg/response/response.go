@@ -18,6 +18,15 @@ type Error struct {
 	Status bool   `json:"status"` // true if we have error
 	Msg    string `json:"msg"`    // error message
 	Code   int    `json:"code"`   // error code from application, it is not http status code
+	// Fields lists the errors of each invalid input field, if any
+	Fields []FieldError `json:"fields,omitempty"`
+}
+
+// FieldError is the error of one input field
+type FieldError struct {
+	Field string `json:"field"`
+	Rule  string `json:"rule"`
+	Msg   string `json:"msg"`
 }
 
 // SetError set the response to return the given error.
@@ -38,15 +47,22 @@ func (res *Response) SetError(err error, code ...int) {
 
 }
 
-// RenderJSON writes the http response in JSON format to the client
+// RenderJSON writes the http response in JSON format to the client.
+// Errors are written as RFC 7807 problem details instead of the envelope
+// when the route uses ProblemDetails or the client accepts application/problem+json.
 func (res *Response) RenderJSON(w http.ResponseWriter, r *http.Request) {
-	w.Header().Set("Content-Type", "application/json")
-
 	if res.StatusCode == 0 {
 		res.StatusCode = http.StatusOK
 	}
 
-	d, err := json.Marshal(res)
+	var body interface{} = res
+	w.Header().Set("Content-Type", "application/json")
+	if res.Error.Status && wantsProblem(r) {
+		body = res.Problem(r)
+		w.Header().Set("Content-Type", ContentTypeProblem)
+	}
+
+	d, err := json.Marshal(body)
 	if err != nil {
 		res.StatusCode = http.StatusInternalServerError
 	}
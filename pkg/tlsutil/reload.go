@@ -0,0 +1,171 @@
+package tlsutil
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sync"
+	"time"
+)
+
+const defaultCheckInterval = 10 * time.Second
+
+// CertReloader serves a certificate and key pair from files and reloads
+// them when they change on disk, e.g. when cert-manager renews a secret.
+// Files are checked at most once per check interval, during handshakes.
+type CertReloader struct {
+	certFile string
+	keyFile  string
+	interval time.Duration
+
+	mu      sync.Mutex
+	cert    *tls.Certificate
+	modTime time.Time
+	checked time.Time
+}
+
+// NewCertReloader loads the key pair and returns its reloader
+func NewCertReloader(certFile, keyFile string) (*CertReloader, error) {
+	r := &CertReloader{
+		certFile: certFile,
+		keyFile:  keyFile,
+		interval: defaultCheckInterval,
+	}
+	if err := r.reload(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// GetCertificate can be used as tls.Config.GetCertificate
+func (r *CertReloader) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	return r.current(), nil
+}
+
+// GetClientCertificate can be used as tls.Config.GetClientCertificate
+func (r *CertReloader) GetClientCertificate(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+	return r.current(), nil
+}
+
+func (r *CertReloader) current() *tls.Certificate {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if time.Since(r.checked) >= r.interval {
+		// keep serving the previous certificate when the new one is broken
+		_ = r.reloadLocked()
+	}
+	return r.cert
+}
+
+func (r *CertReloader) reload() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.reloadLocked()
+}
+
+func (r *CertReloader) reloadLocked() error {
+	r.checked = time.Now()
+
+	modTime, err := latestModTime(r.certFile, r.keyFile)
+	if err != nil {
+		return err
+	}
+	if r.cert != nil && !modTime.After(r.modTime) {
+		return nil
+	}
+
+	cert, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+	if err != nil {
+		return err
+	}
+	r.cert = &cert
+	r.modTime = modTime
+	return nil
+}
+
+// CAReloader serves a CA pool from a PEM file and reloads it when it changes
+type CAReloader struct {
+	file     string
+	interval time.Duration
+
+	mu      sync.Mutex
+	pool    *x509.CertPool
+	modTime time.Time
+	checked time.Time
+}
+
+// NewCAReloader loads the CA file and returns its reloader
+func NewCAReloader(file string) (*CAReloader, error) {
+	r := &CAReloader{
+		file:     file,
+		interval: defaultCheckInterval,
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := r.reloadLocked(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Pool returns the current CA pool
+func (r *CAReloader) Pool() *x509.CertPool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if time.Since(r.checked) >= r.interval {
+		_ = r.reloadLocked()
+	}
+	return r.pool
+}
+
+func (r *CAReloader) reloadLocked() error {
+	r.checked = time.Now()
+
+	modTime, err := latestModTime(r.file)
+	if err != nil {
+		return err
+	}
+	if r.pool != nil && !modTime.After(r.modTime) {
+		return nil
+	}
+
+	pool, err := loadCAPool(r.file)
+	if err != nil {
+		return err
+	}
+	r.pool = pool
+	r.modTime = modTime
+	return nil
+}
+
+func loadCAPool(file string) (*x509.CertPool, error) {
+	pem, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("no certificate found in %s", file)
+	}
+	return pool, nil
+}
+
+func latestModTime(files ...string) (time.Time, error) {
+	var latest time.Time
+	for _, f := range files {
+		fi, err := os.Stat(f)
+		if err != nil {
+			return time.Time{}, err
+		}
+		if fi.ModTime().After(latest) {
+			latest = fi.ModTime()
+		}
+	}
+	return latest, nil
+}
@@ -0,0 +1,179 @@
+// Package tlsutil builds TLS and mutual TLS configurations for servers and
+// clients with certificates that are reloaded when they change on disk.
+package tlsutil
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"strings"
+)
+
+// Client authentication policies, see tls.ClientAuthType
+const (
+	ClientAuthNone             = "none"
+	ClientAuthRequest          = "request"
+	ClientAuthRequireAny       = "require_any"
+	ClientAuthVerifyIfGiven    = "verify_if_given"
+	ClientAuthRequireAndVerify = "require_and_verify"
+)
+
+// Cipher policies
+const (
+	// CipherPolicyDefault uses the Go defaults
+	CipherPolicyDefault = "default"
+	// CipherPolicyModern only allows forward secret AEAD suites
+	CipherPolicyModern = "modern"
+)
+
+// ServerOptions configures a server TLS config
+type ServerOptions struct {
+	CertFile string
+	KeyFile  string
+	// ClientCAFile enables client certificate verification
+	ClientCAFile string
+	// ClientAuth is one of the ClientAuth* policies, it defaults to
+	// ClientAuthRequireAndVerify when ClientCAFile is set
+	ClientAuth string
+	// MinVersion is "1.0", "1.1", "1.2" or "1.3", "1.2" when empty
+	MinVersion   string
+	CipherPolicy string
+}
+
+// ClientOptions configures a client TLS config
+type ClientOptions struct {
+	// CertFile and KeyFile are the client certificate presented for mutual TLS
+	CertFile string
+	KeyFile  string
+	// CAFile replaces the system roots to verify servers
+	CAFile     string
+	ServerName string
+	MinVersion string
+}
+
+// NewServerConfig builds a server TLS config
+func NewServerConfig(opts ServerOptions) (*tls.Config, error) {
+	certs, err := NewCertReloader(opts.CertFile, opts.KeyFile)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := &tls.Config{
+		GetCertificate: certs.GetCertificate,
+	}
+	if cfg.MinVersion, err = parseVersion(opts.MinVersion); err != nil {
+		return nil, err
+	}
+	if cfg.CipherSuites, err = cipherSuites(opts.CipherPolicy); err != nil {
+		return nil, err
+	}
+
+	clientAuth := opts.ClientAuth
+	if clientAuth == "" && opts.ClientCAFile != "" {
+		clientAuth = ClientAuthRequireAndVerify
+	}
+	if cfg.ClientAuth, err = parseClientAuth(clientAuth); err != nil {
+		return nil, err
+	}
+
+	if opts.ClientCAFile != "" {
+		cas, err := NewCAReloader(opts.ClientCAFile)
+		if err != nil {
+			return nil, err
+		}
+		cfg.ClientCAs = cas.Pool()
+		// pick up a renewed CA bundle for new connections
+		cfg.GetConfigForClient = func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			c := cfg.Clone()
+			c.ClientCAs = cas.Pool()
+			c.GetConfigForClient = nil
+			return c, nil
+		}
+	}
+
+	return cfg, nil
+}
+
+// NewClientConfig builds a client TLS config
+func NewClientConfig(opts ClientOptions) (*tls.Config, error) {
+	var err error
+
+	cfg := &tls.Config{
+		ServerName: opts.ServerName,
+	}
+	if cfg.MinVersion, err = parseVersion(opts.MinVersion); err != nil {
+		return nil, err
+	}
+
+	if opts.CertFile != "" {
+		certs, err := NewCertReloader(opts.CertFile, opts.KeyFile)
+		if err != nil {
+			return nil, err
+		}
+		cfg.GetClientCertificate = certs.GetClientCertificate
+	}
+
+	if opts.CAFile != "" {
+		if cfg.RootCAs, err = loadCAPool(opts.CAFile); err != nil {
+			return nil, err
+		}
+	}
+
+	return cfg, nil
+}
+
+// PeerIdentity returns the verified client certificate of a connection, nil if there is none
+func PeerIdentity(state *tls.ConnectionState) *x509.Certificate {
+	if state == nil || len(state.VerifiedChains) == 0 || len(state.VerifiedChains[0]) == 0 {
+		return nil
+	}
+	return state.VerifiedChains[0][0]
+}
+
+func parseVersion(v string) (uint16, error) {
+	switch v {
+	case "1.0":
+		return tls.VersionTLS10, nil
+	case "1.1":
+		return tls.VersionTLS11, nil
+	case "", "1.2":
+		return tls.VersionTLS12, nil
+	case "1.3":
+		return tls.VersionTLS13, nil
+	}
+	return 0, fmt.Errorf("unsupported TLS version %q", v)
+}
+
+func parseClientAuth(v string) (tls.ClientAuthType, error) {
+	switch v {
+	case "", ClientAuthNone:
+		return tls.NoClientCert, nil
+	case ClientAuthRequest:
+		return tls.RequestClientCert, nil
+	case ClientAuthRequireAny:
+		return tls.RequireAnyClientCert, nil
+	case ClientAuthVerifyIfGiven:
+		return tls.VerifyClientCertIfGiven, nil
+	case ClientAuthRequireAndVerify:
+		return tls.RequireAndVerifyClientCert, nil
+	}
+	return 0, fmt.Errorf("unsupported client auth %q", v)
+}
+
+func cipherSuites(policy string) ([]uint16, error) {
+	switch strings.ToLower(policy) {
+	case "", CipherPolicyDefault:
+		return nil, nil
+	case CipherPolicyModern:
+		// TLS 1.3 suites are not configurable and always forward secret AEAD
+		return []uint16{
+			tls.TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256,
+			tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256,
+			tls.TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384,
+			tls.TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384,
+			tls.TLS_ECDHE_ECDSA_WITH_CHACHA20_POLY1305,
+			tls.TLS_ECDHE_RSA_WITH_CHACHA20_POLY1305,
+		}, nil
+	}
+	return nil, fmt.Errorf("unsupported cipher policy %q", policy)
+}
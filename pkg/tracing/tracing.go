@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/opentracing/opentracing-go"
+	jaeger "github.com/uber/jaeger-client-go"
 	"github.com/uber/jaeger-client-go/config"
 	"go.uber.org/zap"
 
@@ -46,3 +47,14 @@ func (l jaegerLoggerAdapter) Error(msg string) {
 func (l jaegerLoggerAdapter) Infof(msg string, args ...interface{}) {
 	l.logger.Info(fmt.Sprintf(msg, args...))
 }
+
+// TraceID returns the trace ID of a Jaeger span, empty for other spans
+func TraceID(span opentracing.Span) string {
+	if span == nil {
+		return ""
+	}
+	if sc, ok := span.Context().(jaeger.SpanContext); ok {
+		return sc.TraceID().String()
+	}
+	return ""
+}